@@ -1,3 +1,8 @@
 module github.com/AnthonyMBonafide/ephemeris
 
 go 1.23.2
+
+require (
+	github.com/pelletier/go-toml/v2 v2.4.3
+	gopkg.in/yaml.v3 v3.0.1
+)
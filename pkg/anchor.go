@@ -0,0 +1,150 @@
+package ephemeris
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrAnchorNotFound an Anchor refers to a Rule which is not in the Calendar.
+	ErrAnchorNotFound = errors.New("anchor rule not found")
+	// ErrAnchorCycle Rules are anchored to each other in a cycle so their
+	// occurrences can not be derived.
+	ErrAnchorCycle = errors.New("anchor rules form a cycle")
+)
+
+// Anchor derives the occurrences of a Rule from the occurrences of another
+// Rule in the same Calendar, for example a meeting to prepare 2 days before
+// each board meeting. Each occurrence of the anchor Rule results in one
+// occurrence starting Offset from its Start, or its End when FromEnd is set,
+// and lasting for Duration. A zero Duration derives instants.
+//
+// The derived occurrences follow the anchor Rule's occurrences, so they move
+// when an occurrence is overridden, are not created for skipped occurrences and
+// are canceled when an occurrence is canceled. Skip, Canceled and Overrides of
+// the anchored Rule apply to the derived occurrences, which have a
+// RecurrenceID derived from the RecurrenceID of the anchor occurrence.
+type Anchor struct {
+	// RuleID the ID of the Rule the occurrences are derived from.
+	RuleID string
+
+	// Offset the time from the anchor occurrence to the start of the derived
+	// occurrence. Negative values start before the anchor occurrence.
+	Offset time.Duration
+
+	// FromEnd use the End of the anchor occurrence instead of its Start.
+	FromEnd bool
+
+	// Duration how long each derived occurrence lasts.
+	Duration time.Duration
+}
+
+// ruleIndex finds the index of the first Rule of the Calendar with the ID
+// other than the Rule at except, so a Rule is never its own anchor.
+func (c *CalendarOf[T]) ruleIndex(id string, except int) (int, bool) {
+	for i, rule := range c.Entries {
+		if i != except && rule.ID == id {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+// expandRule creates events like Rule.Expand for the Rule at the index of
+// Entries while also deriving the occurrences of Rules with an Anchor from the
+// other Rules of the Calendar.
+func (c *CalendarOf[T]) expandRule(index int, viewStart, viewEnd time.Time) ([]EventOf[T], error) {
+	return c.expandAnchored(index, viewStart, viewEnd, nil)
+}
+
+// expandAnchored expands the Rule at the index of Entries where anchoredBy
+// contains the indexes of the Rules which are being expanded and depend on
+// this Rule, which is used to detect cycles. Indexes are used rather than IDs
+// as IDs are optional and do not have to be unique.
+func (c *CalendarOf[T]) expandAnchored(index int, viewStart, viewEnd time.Time, anchoredBy []int) ([]EventOf[T], error) {
+	r := c.Entries[index]
+	if r.Anchor == nil {
+		return r.Expand(viewStart, viewEnd), nil
+	}
+
+	if slices.Contains(anchoredBy, index) {
+		return nil, c.anchorCycle(append(anchoredBy[slices.Index(anchoredBy, index):], index))
+	}
+
+	if r.Anchor.RuleID == "" {
+		return nil, fmt.Errorf("rule %q is not anchored to a rule ID: %w", r.displayName(), ErrAnchorNotFound)
+	}
+	anchorIndex, ok := c.ruleIndex(r.Anchor.RuleID, index)
+	if !ok && r.ID == r.Anchor.RuleID {
+		return nil, c.anchorCycle([]int{index, index})
+	}
+	if !ok {
+		return nil, fmt.Errorf("rule %q is anchored to %q: %w", r.displayName(), r.Anchor.RuleID, ErrAnchorNotFound)
+	}
+
+	// Only anchor occurrences which result in derived occurrences within the
+	// view are needed
+	anchorViewStart := viewStart.Add(-r.Anchor.Offset - r.Anchor.Duration)
+	anchorViewEnd := viewEnd.Add(-r.Anchor.Offset)
+	anchorEvents, err := c.expandAnchored(anchorIndex, anchorViewStart, anchorViewEnd, append(slices.Clip(anchoredBy), index))
+	if err != nil {
+		return nil, err
+	}
+
+	var expandedEvents []EventOf[T]
+	for _, anchorEvent := range anchorEvents {
+		start := anchorEvent.Start.Add(r.Anchor.Offset)
+		if r.Anchor.FromEnd {
+			if anchorEvent.IsOpenEnded() {
+				// There is no End to derive an occurrence from
+				continue
+			}
+			start = anchorEvent.End.Add(r.Anchor.Offset)
+		}
+
+		occurrence, ok := r.occurrenceBetween(start, start.Add(r.Anchor.Duration))
+		if !ok {
+			continue
+		}
+		// Keep the link to the original anchor occurrence even if it was moved
+		occurrence.RecurrenceID = anchorEvent.RecurrenceID.Add(start.Sub(anchorEvent.Start))
+		if anchorEvent.Status == StatusCanceled {
+			occurrence.Status = StatusCanceled
+		}
+		if override, ok := r.override(occurrence.RecurrenceID); ok {
+			occurrence = applyOverride(occurrence, override)
+		}
+
+		if isInView(occurrence, viewStart, viewEnd) {
+			expandedEvents = append(expandedEvents, occurrence)
+		}
+	}
+
+	slices.SortStableFunc(expandedEvents, func(a, b EventOf[T]) int {
+		return a.Start.Compare(b.Start)
+	})
+
+	return expandedEvents, nil
+}
+
+// anchorCycle the error for the Rules at the indexes anchoring each other in a
+// cycle.
+func (c *CalendarOf[T]) anchorCycle(indexes []int) error {
+	names := make([]string, len(indexes))
+	for i, index := range indexes {
+		names[i] = c.Entries[index].displayName()
+	}
+	return fmt.Errorf("%w: %s", ErrAnchorCycle, strings.Join(names, " -> "))
+}
+
+// displayName a name for the Rule to use in errors.
+func (r RuleOf[T]) displayName() string {
+	if r.ID != "" {
+		return r.ID
+	}
+	return r.Name
+}
@@ -0,0 +1,109 @@
+package ephemeris
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestAnchoredRules(t *testing.T) {
+	board := Rule{
+		ID: "board",
+		Event: Event{
+			Start: time.Date(2024, time.January, 10, 14, 0, 0, 0, time.UTC),
+			End:   time.Date(2024, time.January, 10, 16, 0, 0, 0, time.UTC),
+			Name:  "Board Meeting",
+		},
+		RepeatDayOfMonthMonthly: 1,
+		Canceled:                []time.Time{time.Date(2024, time.March, 10, 14, 0, 0, 0, time.UTC)},
+		Overrides: []Event{
+			{RecurrenceID: time.Date(2024, time.February, 10, 14, 0, 0, 0, time.UTC), Start: time.Date(2024, time.February, 12, 14, 0, 0, 0, time.UTC)},
+		},
+	}
+	prep := Rule{
+		ID:     "prep",
+		Event:  Event{Name: "Board Prep"},
+		Anchor: &Anchor{RuleID: "board", Offset: -48 * time.Hour, Duration: time.Hour},
+	}
+	followUp := Rule{
+		Event:  Event{Name: "Follow Up"},
+		Anchor: &Anchor{RuleID: "board", Offset: 30 * time.Minute, FromEnd: true, Duration: 15 * time.Minute},
+	}
+	prepReminder := Rule{
+		Event:  Event{Name: "Prep Reminder"},
+		Anchor: &Anchor{RuleID: "prep", Offset: -24 * time.Hour, Duration: 5 * time.Minute},
+	}
+
+	calendar := Calendar{Entries: []Rule{board, prep, followUp, prepReminder}}
+	got, err := calendar.View(time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Event{
+		{Start: time.Date(2024, time.February, 9, 14, 0, 0, 0, time.UTC), End: time.Date(2024, time.February, 9, 14, 5, 0, 0, time.UTC), Name: "Prep Reminder", RecurrenceID: time.Date(2024, time.February, 7, 14, 0, 0, 0, time.UTC)},
+		{Start: time.Date(2024, time.February, 10, 14, 0, 0, 0, time.UTC), End: time.Date(2024, time.February, 10, 15, 0, 0, 0, time.UTC), Name: "Board Prep", RecurrenceID: time.Date(2024, time.February, 8, 14, 0, 0, 0, time.UTC), RuleID: "prep"},
+		{Start: time.Date(2024, time.February, 12, 14, 0, 0, 0, time.UTC), End: time.Date(2024, time.February, 12, 16, 0, 0, 0, time.UTC), Name: "Board Meeting", RecurrenceID: time.Date(2024, time.February, 10, 14, 0, 0, 0, time.UTC), RuleID: "board"},
+		{Start: time.Date(2024, time.February, 12, 16, 30, 0, 0, time.UTC), End: time.Date(2024, time.February, 12, 16, 45, 0, 0, time.UTC), Name: "Follow Up", RecurrenceID: time.Date(2024, time.February, 10, 16, 30, 0, 0, time.UTC)},
+	}
+	if !slices.Equal(expected, got) {
+		t.Errorf("Expected %v but got %v", expected, got)
+	}
+}
+
+func TestAnchoredRuleErrors(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		entries  []Rule
+		expected error
+	}{
+		{
+			desc: "Missing Anchor",
+			entries: []Rule{
+				{ID: "a", Event: Event{Name: "A"}, Anchor: &Anchor{RuleID: "missing", Duration: time.Hour}},
+			},
+			expected: ErrAnchorNotFound,
+		},
+		{
+			desc: "Anchored To Itself",
+			entries: []Rule{
+				{ID: "a", Event: Event{Name: "A"}, Anchor: &Anchor{RuleID: "a", Duration: time.Hour}},
+			},
+			expected: ErrAnchorCycle,
+		},
+		{
+			desc: "Anchored Without A Rule ID",
+			entries: []Rule{
+				{Event: Event{Name: "A"}, Anchor: &Anchor{Duration: time.Hour}},
+			},
+			expected: ErrAnchorNotFound,
+		},
+		{
+			desc: "Cycle Between Rules With The Same ID",
+			entries: []Rule{
+				{ID: "a", Event: Event{Name: "A"}, Anchor: &Anchor{RuleID: "a", Duration: time.Hour}},
+				{ID: "a", Event: Event{Name: "B"}, Anchor: &Anchor{RuleID: "a", Duration: time.Hour}},
+			},
+			expected: ErrAnchorCycle,
+		},
+		{
+			desc: "Cycle",
+			entries: []Rule{
+				{ID: "a", Event: Event{Name: "A"}, Anchor: &Anchor{RuleID: "b", Duration: time.Hour}},
+				{ID: "b", Event: Event{Name: "B"}, Anchor: &Anchor{RuleID: "c", Duration: time.Hour}},
+				{ID: "c", Event: Event{Name: "C"}, Anchor: &Anchor{RuleID: "a", Duration: time.Hour}},
+			},
+			expected: ErrAnchorCycle,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			calendar := Calendar{Entries: tC.entries}
+			_, err := calendar.View(rightNow, rightNow.AddDate(0, 0, 7))
+			if !errors.Is(err, tC.expected) {
+				t.Errorf("Expected error %v but got %v", tC.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package ephemeris
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNoBusinessDay there is no business day for too long to keep looking, such
+// as when the holidays Calendar has an open ended Event.
+var ErrNoBusinessDay = errors.New("no business day found")
+
+// IsBusinessDay determines if the day of t, in the location of t, is a
+// business day. Business days are Monday to Friday which do not have any part
+// covered by an Event of the holidays Calendar which is not canceled. A nil
+// holidays Calendar has no holidays.
+func IsBusinessDay(t time.Time, holidays *Calendar) bool {
+	return newBusinessDays(holidays, t.Location()).isBusinessDay(t)
+}
+
+// AddBusinessDays returns the time n business days after t, keeping the time
+// of day of t. A negative n moves back to the nth business day before t. See
+// IsBusinessDay for which days are business days.
+//
+// An error is returned when there is no business day for 28 years, which is
+// when every combination of the days of the week and holidays which repeat
+// yearly has been seen.
+func AddBusinessDays(t time.Time, n int, holidays *Calendar) (time.Time, error) {
+	return newBusinessDays(holidays, t.Location()).add(t, n)
+}
+
+// BusinessDaysBetween counts the business days from the day of start up to,
+// but not including, the day of end using the location of start. When end is
+// before start the result is negative. See IsBusinessDay for which days are
+// business days.
+func BusinessDaysBetween(start, end time.Time, holidays *Calendar) int {
+	return newBusinessDays(holidays, start.Location()).between(start, end)
+}
+
+// businessDays determines which days are business days in a location. Holidays
+// are expanded from the holiday Calendar a month at a time as they are needed.
+type businessDays struct {
+	holidays *Calendar
+	loc      *time.Location
+
+	// holidaysByMonth the days of each month which are holidays, keyed by
+	// the first of the month.
+	holidaysByMonth map[time.Time]map[int]bool
+}
+
+func newBusinessDays(holidays *Calendar, loc *time.Location) *businessDays {
+	return &businessDays{
+		holidays:        holidays,
+		loc:             loc,
+		holidaysByMonth: map[time.Time]map[int]bool{},
+	}
+}
+
+func (b *businessDays) isBusinessDay(t time.Time) bool {
+	t = t.In(b.loc)
+	if t.Weekday() == time.Saturday || t.Weekday() == time.Sunday {
+		return false
+	}
+	if b.holidays == nil {
+		return true
+	}
+
+	month := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, b.loc)
+	holidays, ok := b.holidaysByMonth[month]
+	if !ok {
+		holidays = b.holidaysIn(month)
+		b.holidaysByMonth[month] = holidays
+	}
+
+	return !holidays[t.Day()]
+}
+
+// holidaysIn finds the days of the month starting at monthStart which are
+// covered by the holiday Calendar.
+func (b *businessDays) holidaysIn(monthStart time.Time) map[int]bool {
+	monthEnd := monthStart.AddDate(0, 1, 0)
+
+	holidays := map[int]bool{}
+	for _, rule := range b.holidays.Entries {
+		for _, holiday := range rule.Expand(monthStart, monthEnd) {
+			if holiday.Status == StatusCanceled {
+				continue
+			}
+
+			start := holiday.Start.In(b.loc)
+			if start.Before(monthStart) {
+				start = monthStart
+			}
+			for day := startOfDay(start); day.Before(holiday.effectiveEnd()) && day.Before(monthEnd); day = day.AddDate(0, 0, 1) {
+				holidays[day.Day()] = true
+			}
+		}
+	}
+
+	return holidays
+}
+
+func (b *businessDays) add(t time.Time, n int) (time.Time, error) {
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+
+	// Give up when the holidays cover every day for too long rather than
+	// looking forever
+	last := t
+	for n > 0 {
+		t = t.AddDate(0, 0, step)
+		if t.Compare(last.AddDate(step*occurrenceSearchYears, 0, 0))*step >= 0 {
+			return time.Time{}, fmt.Errorf("%w within %d years of %s", ErrNoBusinessDay, occurrenceSearchYears, last.Format(time.DateOnly))
+		}
+		if b.isBusinessDay(t) {
+			last = t
+			n--
+		}
+	}
+
+	return t, nil
+}
+
+func (b *businessDays) between(start, end time.Time) int {
+	first, last, sign := startOfDay(start.In(b.loc)), startOfDay(end.In(b.loc)), 1
+	if last.Before(first) {
+		first, last, sign = last, first, -1
+	}
+
+	count := 0
+	for day := first; day.Before(last); day = day.AddDate(0, 0, 1) {
+		if b.isBusinessDay(day) {
+			count++
+		}
+	}
+
+	return sign * count
+}
+
+// nthOfMonth finds the nth business day of the month, counting back from the
+// end of the month when n is negative.
+func (b *businessDays) nthOfMonth(monthStart time.Time, n int) (time.Time, bool) {
+	day, step := monthStart, 1
+	if n < 0 {
+		day, step, n = monthStart.AddDate(0, 1, -1), -1, -n
+	}
+
+	for ; day.Month() == monthStart.Month(); day = day.AddDate(0, 0, step) {
+		if !b.isBusinessDay(day) {
+			continue
+		}
+		n--
+		if n == 0 {
+			return day, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// businessOccurrences generates the occurrences of a Rule using
+// RepeatBusinessDaily or RepeatBusinessDayOfMonth which are active within the
+// timeframe.
+func (r RuleOf[T]) businessOccurrences(viewStart, viewEnd time.Time) []EventOf[T] {
+	loc := r.Start.Location()
+	days := newBusinessDays(r.Holidays, loc)
+
+	first := startOfDay(viewStart.In(loc))
+	if !r.IsOpenEnded() {
+		// Events which start before the view can still be active within it
+		first = startOfDay(viewStart.Add(-r.End.Sub(r.Start)).In(loc))
+	}
+	last := viewEnd.In(loc)
+
+	var occurrences []EventOf[T]
+	addOccurrence := func(day time.Time) {
+		start := time.Date(day.Year(), day.Month(), day.Day(), r.Start.Hour(), r.Start.Minute(), r.Start.Second(), r.Start.Nanosecond(), loc)
+		if !r.isWithinRepeatBounds(start) {
+			return
+		}
+
+		occurrence, ok := r.occurrenceBetween(start, r.endFor(start))
+		if ok && isInView(occurrence, viewStart, viewEnd) {
+			occurrences = append(occurrences, occurrence)
+		}
+	}
+
+	if r.RepeatBusinessDaily > 0 {
+		// Business days are counted from the Start so the same days are used
+		// no matter when the view starts.
+		index := days.between(r.Start, first)
+		for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+			if !days.isBusinessDay(day) {
+				continue
+			}
+			if index%r.RepeatBusinessDaily == 0 {
+				addOccurrence(day)
+			}
+			index++
+		}
+
+		return occurrences
+	}
+
+	for month := time.Date(first.Year(), first.Month(), 1, 0, 0, 0, 0, loc); !month.After(last); month = month.AddDate(0, 1, 0) {
+		if day, ok := days.nthOfMonth(month, r.RepeatBusinessDayOfMonth); ok {
+			addOccurrence(day)
+		}
+	}
+
+	return occurrences
+}
+
+// startOfDay midnight at the start of the day of t in the location of t.
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
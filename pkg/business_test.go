@@ -0,0 +1,221 @@
+package ephemeris
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// independenceDay a holiday Calendar with July 4th 2024, a Thursday
+var independenceDay = &Calendar{
+	Name: "Holidays",
+	Entries: []Rule{
+		{
+			Event: Event{
+				Start: time.Date(2024, time.July, 4, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2024, time.July, 5, 0, 0, 0, 0, time.UTC),
+				Name:  "Independence Day",
+			},
+		},
+	},
+}
+
+func TestAddBusinessDays(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		t        time.Time
+		n        int
+		holidays *Calendar
+		expected time.Time
+		err      error
+	}{
+		{
+			desc:     "Zero Days",
+			t:        time.Date(2024, time.July, 6, 9, 0, 0, 0, time.UTC),
+			n:        0,
+			expected: time.Date(2024, time.July, 6, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			desc:     "Over A Weekend",
+			t:        time.Date(2024, time.July, 5, 9, 0, 0, 0, time.UTC),
+			n:        1,
+			expected: time.Date(2024, time.July, 8, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			desc:     "Over A Holiday",
+			t:        time.Date(2024, time.July, 3, 9, 0, 0, 0, time.UTC),
+			n:        2,
+			holidays: independenceDay,
+			expected: time.Date(2024, time.July, 8, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			desc:     "Backwards Over A Holiday And Weekend",
+			t:        time.Date(2024, time.July, 8, 9, 0, 0, 0, time.UTC),
+			n:        -2,
+			holidays: independenceDay,
+			expected: time.Date(2024, time.July, 3, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			desc: "Open Ended Holiday",
+			t:    time.Date(2024, time.July, 3, 9, 0, 0, 0, time.UTC),
+			n:    2,
+			holidays: &Calendar{Entries: []Rule{
+				{Event: Event{Start: time.Date(2024, time.July, 4, 0, 0, 0, 0, time.UTC), Name: "Strike"}},
+			}},
+			err: ErrNoBusinessDay,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			got, err := AddBusinessDays(tC.t, tC.n, tC.holidays)
+			if !errors.Is(err, tC.err) {
+				t.Fatalf("Expected the error %v but got %v", tC.err, err)
+			}
+			if !got.Equal(tC.expected) {
+				t.Errorf("Expected %s but got %s", tC.expected, got)
+			}
+		})
+	}
+}
+
+func TestBusinessDaysBetween(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		start    time.Time
+		end      time.Time
+		holidays *Calendar
+		expected int
+	}{
+		{
+			desc:     "Same Day",
+			start:    time.Date(2024, time.July, 1, 9, 0, 0, 0, time.UTC),
+			end:      time.Date(2024, time.July, 1, 17, 0, 0, 0, time.UTC),
+			expected: 0,
+		},
+		{
+			desc:     "Two Weeks",
+			start:    time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC),
+			end:      time.Date(2024, time.July, 15, 0, 0, 0, 0, time.UTC),
+			expected: 10,
+		},
+		{
+			desc:     "Two Weeks With A Holiday",
+			start:    time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC),
+			end:      time.Date(2024, time.July, 15, 0, 0, 0, 0, time.UTC),
+			holidays: independenceDay,
+			expected: 9,
+		},
+		{
+			desc:     "End Before Start",
+			start:    time.Date(2024, time.July, 15, 0, 0, 0, 0, time.UTC),
+			end:      time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC),
+			holidays: independenceDay,
+			expected: -9,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			got := BusinessDaysBetween(tC.start, tC.end, tC.holidays)
+			if got != tC.expected {
+				t.Errorf("Expected %d but got %d", tC.expected, got)
+			}
+		})
+	}
+}
+
+func TestExpandBusinessDays(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		rule      Rule
+		viewStart time.Time
+		viewEnd   time.Time
+		expected  []time.Time
+	}{
+		{
+			desc: "Every Business Day",
+			rule: Rule{
+				Event: Event{
+					Start: time.Date(2024, time.June, 3, 9, 0, 0, 0, time.UTC),
+					End:   time.Date(2024, time.June, 3, 9, 15, 0, 0, time.UTC),
+					Name:  "Standup",
+				},
+				RepeatBusinessDaily: 1,
+				Holidays:            independenceDay,
+			},
+			viewStart: time.Date(2024, time.July, 3, 0, 0, 0, 0, time.UTC),
+			viewEnd:   time.Date(2024, time.July, 10, 0, 0, 0, 0, time.UTC),
+			expected: []time.Time{
+				time.Date(2024, time.July, 3, 9, 0, 0, 0, time.UTC),
+				time.Date(2024, time.July, 5, 9, 0, 0, 0, time.UTC),
+				time.Date(2024, time.July, 8, 9, 0, 0, 0, time.UTC),
+				time.Date(2024, time.July, 9, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			desc: "Every Other Business Day Counted From The Start",
+			rule: Rule{
+				Event: Event{
+					Start: time.Date(2024, time.July, 1, 9, 0, 0, 0, time.UTC),
+					End:   time.Date(2024, time.July, 1, 9, 15, 0, 0, time.UTC),
+					Name:  "Sync",
+				},
+				RepeatBusinessDaily: 2,
+				Holidays:            independenceDay,
+			},
+			viewStart: time.Date(2024, time.July, 2, 0, 0, 0, 0, time.UTC),
+			viewEnd:   time.Date(2024, time.July, 10, 0, 0, 0, 0, time.UTC),
+			expected: []time.Time{
+				time.Date(2024, time.July, 3, 9, 0, 0, 0, time.UTC),
+				time.Date(2024, time.July, 8, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			desc: "Fourth Business Day Of Each Month",
+			rule: Rule{
+				Event: Event{
+					Start: time.Date(2024, time.January, 3, 12, 0, 0, 0, time.UTC),
+					End:   time.Date(2024, time.January, 3, 13, 0, 0, 0, time.UTC),
+					Name:  "Payroll",
+				},
+				RepeatBusinessDayOfMonth: 4,
+				Holidays:                 independenceDay,
+			},
+			viewStart: time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC),
+			viewEnd:   time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC),
+			expected: []time.Time{
+				time.Date(2024, time.June, 6, 12, 0, 0, 0, time.UTC),
+				time.Date(2024, time.July, 5, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			desc: "Last Business Day Of Each Month",
+			rule: Rule{
+				Event: Event{
+					Start: time.Date(2024, time.January, 31, 16, 0, 0, 0, time.UTC),
+					End:   time.Date(2024, time.January, 31, 17, 0, 0, 0, time.UTC),
+					Name:  "Close",
+				},
+				RepeatBusinessDayOfMonth: -1,
+			},
+			viewStart: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+			viewEnd:   time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC),
+			expected: []time.Time{
+				time.Date(2024, time.March, 29, 16, 0, 0, 0, time.UTC),
+				time.Date(2024, time.April, 30, 16, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			var got []time.Time
+			for _, e := range tC.rule.Expand(tC.viewStart, tC.viewEnd) {
+				got = append(got, e.Start)
+			}
+
+			if !slices.EqualFunc(tC.expected, got, time.Time.Equal) {
+				t.Errorf("Expected %v but got %v", tC.expected, got)
+			}
+		})
+	}
+}
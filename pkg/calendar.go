@@ -1,7 +1,11 @@
 package ephemeris
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"reflect"
 	"slices"
 	"time"
 )
@@ -16,39 +20,221 @@ import (
 // can only be doing one thing at a time. Similar to Google calendar and other digital calendars where Events
 // can be created, repeated, cancelled, ect. However only one Event is displayed(with other conflicts being shown under
 // the one which takes priority)
-type Calendar struct {
+//
+// The Events of a CalendarOf carry a Payload of type T, such as a thermostat
+// setpoint or the person who is on call, so the state they represent does not
+// need to be encoded into their Name. Calendar is the CalendarOf Events which
+// are only described by their Name.
+type CalendarOf[T any] struct {
 	Name string
 	// Perserve order here, the later events take precidence. Like a calendar the later events were made with the ealier ones in mind
-	Entries []Rule
+	Entries []RuleOf[T]
+
+	// Merge combines touching Events with the same identity after condensing,
+	// see MergeBy.
+	Merge MergeBy
+
+	// MinimumDuration Events which condensing truncated to less than this
+	// are removed from the View, such as a few minutes of an Event left
+	// between two Events with a higher priority.
+	MinimumDuration time.Duration
+
+	// AbsorbShortEvents the time of Events removed by MinimumDuration is
+	// covered by the Event which ends at their Start, or otherwise the one
+	// which starts at their End, instead of being left empty.
+	AbsorbShortEvents bool
+
+	// Idle an Event used to fill the parts of the View which are not covered
+	// by any Event, so the View is contiguous. Only the Start and End of the
+	// Idle Event are changed.
+	Idle *EventOf[T]
+
+	// WorkingHours limits the times FreeSlots are found in. A nil
+	// WorkingHours allows free time at any time.
+	WorkingHours *WorkingHours
+
+	// Tracks the capacity of the Tracks Events are assigned to, used by
+	// TrackViews. Tracks which are not listed have a Capacity of 1.
+	Tracks []Track
 }
 
-func (c Calendar) String() string {
+// Calendar a CalendarOf Events without a Payload.
+type Calendar = CalendarOf[struct{}]
+
+func (c CalendarOf[T]) String() string {
 	panic("TODO implement list of events")
 }
 
-func (c Calendar) StringForView(viewStart, viewEnd time.Time) {
+func (c CalendarOf[T]) StringForView(viewStart, viewEnd time.Time) {
 	panic("TODO implement list of events for given timeframe")
 }
 
-func (c Calendar) AsciiForView(viewStart, viewEnd time.Time) {
+func (c CalendarOf[T]) AsciiForView(viewStart, viewEnd time.Time) {
 	panic("TODO implement text user interface for viewing of events for the given timeframe in terminal/text")
 }
 
 // Event represents an entry on a calendar which can represent the state of something.
-type Event struct {
+//
+// An Event with a zero End is open ended, it lasts until further notice. An
+// Event with the same Start and End is an instant, such as a deploy happening
+// or an alarm going off, which does not take up any time.
+type EventOf[T any] struct {
 	Start time.Time
 	End   time.Time
 	Name  string
+
+	// Payload the state the Event represents. It is copied to every Event
+	// expanded from a Rule and to the fragments condensing splits them into.
+	Payload T
+
+	// Status the state of the Event, for example an occurrence of a repeating
+	// Rule which has been canceled.
+	Status Status
+
+	// RecurrenceID the original Start of the occurrence this Event was derived
+	// from when it was created by repeating a Rule. This remains the same when
+	// an occurrence is moved by an override, similar to an iCalendar RECURRENCE-ID,
+	// which keeps the Event linked to the occurrence of the Rule it came from.
+	RecurrenceID time.Time
+
+	// RuleID the ID of the Rule the Event was expanded from.
+	RuleID string
+
+	// Truncated part of the Event was removed by condensing because it
+	// overlapped an Event with a higher priority, so its Start or End differs
+	// from the occurrence it came from.
+	Truncated bool
+
+	// Split condensing divided the occurrence the Event came from into more
+	// than one Event, each of which is also Truncated.
+	Split bool
+
+	// Layer the Name of the Calendar the Event came from when Calendars are
+	// combined with CalendarOf.Overlay.
+	Layer string
+
+	// Track the name of the Track, such as a room or a member of a team, the
+	// Event is assigned to, see CalendarOf.TrackViews.
+	Track string
+
+	// AllDay the Event lasts for whole days where only the dates of Start and
+	// End, in their own location, are used. End is the day after the last day
+	// of the Event, like an iCalendar DTEND with VALUE=DATE, so a single day
+	// Event on February 13th has a Start on the 13th and an End on the 14th.
+	// WriteICalendar and ReadICalendar use DATE values for them.
+	//
+	// All day Events are expanded to midnight on their dates in the location of
+	// the view, so they cover the same dates for every viewer instead of
+	// shifting like Events which start at midnight in a location. During
+	// condensing they have the same precedence as other Events, by the order
+	// of the Rules, so an all day Event only hides the parts of timed Events
+	// from earlier Rules and is split around timed Events from later Rules.
+	AllDay bool
+
+	// Metadata optional information about the Event. It is shared by all the
+	// Events expanded from a Rule and the fragments condensing splits them
+	// into, so it should not be modified after it is added to an Event.
+	Metadata *Metadata
 }
 
+// Metadata additional information about an Event which does not affect when it
+// takes place.
+type Metadata struct {
+	Description string `json:"description,omitempty"`
+	// Location where the Event takes place, such as an address or room.
+	Location string   `json:"location,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	// Color used to display the Event, such as "#ff0000".
+	Color string `json:"color,omitempty"`
+	// Attributes arbitrary key value pairs for information which does not
+	// have a field.
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// Event an EventOf without a Payload.
+type Event = EventOf[struct{}]
+
+// withPayload copies the Event with the Payload replaced, possibly of another
+// type.
+func withPayload[T, U any](e EventOf[U], payload T) EventOf[T] {
+	return EventOf[T]{
+		Start:        e.Start,
+		End:          e.End,
+		Name:         e.Name,
+		Payload:      payload,
+		Status:       e.Status,
+		RecurrenceID: e.RecurrenceID,
+		RuleID:       e.RuleID,
+		Truncated:    e.Truncated,
+		Split:        e.Split,
+		Layer:        e.Layer,
+		Track:        e.Track,
+		AllDay:       e.AllDay,
+		Metadata:     e.Metadata,
+	}
+}
+
+// HasTag determines if the Event's Metadata contains the tag.
+func (e EventOf[T]) HasTag(tag string) bool {
+	return e.Metadata != nil && slices.Contains(e.Metadata.Tags, tag)
+}
+
+// IsOpenEnded determines if the Event lasts until further notice.
+func (e EventOf[T]) IsOpenEnded() bool {
+	return e.End.IsZero()
+}
+
+// IsInstant determines if the Event happens at a single point in time.
+func (e EventOf[T]) IsInstant() bool {
+	return !e.End.IsZero() && e.End.Equal(e.Start)
+}
+
+// endOfTime the latest time which can be represented, used as the End of open
+// ended Events when comparing them to other Events.
+var endOfTime = time.Unix(1<<63-1-62135596800, 999999999)
+
+// effectiveEnd the End of the Event where open ended Events end at the end of
+// time.
+func (e EventOf[T]) effectiveEnd() time.Time {
+	if e.IsOpenEnded() {
+		return endOfTime
+	}
+	return e.End
+}
+
+// Status describes the state of an Event.
+type Status int
+
+const (
+	// StatusConfirmed the default state of an Event.
+	StatusConfirmed Status = iota
+	// StatusCanceled the Event will not take place but is still kept on the
+	// Calendar, for example so that it can be displayed as canceled.
+	StatusCanceled
+)
+
 // Rule additional information about an Event which provides functionality for
 // repeating, skipping, or canceling Events. Rules should be persisted so that
 // Events can always be derived for a given time window.
 //
 // 0 values for Repeat, Skip, or Canceled result in that feature not being used.
-type Rule struct {
+//
+// The Event being repeated is embedded so its fields can be used directly. The
+// Payload of the Event is kept on the Rule so the embedded field is always an
+// Event, no matter the type of the Payload.
+type RuleOf[T any] struct {
 	Event
 
+	// Payload the state the Events expanded from the Rule represent.
+	Payload T
+
+	// ID identifies the Rule within a Calendar so other Rules can refer to it,
+	// for example with an Anchor, and so Events can be linked back to the Rule
+	// they came from with their RuleID. It should not change once the Rule is
+	// persisted, CalendarOf.AssignIDs creates IDs for Rules which do not have
+	// one.
+	ID string
+
 	// RepeatDuration the duration at which to repeat the event from the Start time.
 	RepeatDuration time.Duration
 
@@ -73,6 +259,67 @@ type Rule struct {
 	// events with the same Start and End time.
 	RepeatDaily int
 
+	// RepeatHourly will repeat an Event every x hours. The hours are counted
+	// using the time of day in the location of the Event's Start, so
+	// occurrences stay on the same minute of the hour and do not drift when
+	// daylight saving time starts or ends.
+	RepeatHourly int
+
+	// RepeatMinutely will repeat an Event every x minutes, counted the same
+	// way as RepeatHourly.
+	RepeatMinutely int
+
+	// RepeatEaster will repeat an Event every year on the date of Easter
+	// Sunday, calculated with the Computus, plus EasterOffsetDays. For example
+	// an offset of -2 is Good Friday and 1 is Easter Monday. The year and time
+	// of day are taken from the Event's Start.
+	RepeatEaster Computus
+
+	// EasterOffsetDays the number of days from Easter Sunday for RepeatEaster.
+	EasterOffsetDays int
+
+	// ByHour restricts repeated Events to the ones which start within the
+	// given hours of the day(0-23) in the location of the Event's Start. For
+	// example repeating every 4 hours with ByHour 8 through 20 results in
+	// Events at 08:00, 12:00, 16:00 and 20:00 each day.
+	ByHour []int
+
+	// ByMinute restricts repeated Events to the ones which start within the
+	// given minutes of the hour(0-59).
+	ByMinute []int
+
+	// Cron will repeat an Event at the times matching a cron expression, see
+	// ParseCron for the supported syntax. The times are matched in the
+	// location of the Event's Start and each Event lasts as long as the
+	// original. Occurrences before the Start are limited by
+	// RepeatBackwardUntil and after it by RepeatForwardUntil, like other
+	// repeat options.
+	//
+	// When Cron is set the other repeat options are not used.
+	Cron string
+
+	// RepeatBusinessDaily will repeat an Event every x business days. Business
+	// days are Monday to Friday, in the location of the Event's Start, which
+	// are not Holidays.
+	RepeatBusinessDaily int
+
+	// RepeatBusinessDayOfMonth will repeat an Event every month on the xth
+	// business day of the month, for example 3 repeats on the third business
+	// day of each month. Negative values count back from the end of the month
+	// so -1 repeats on the last business day of each month.
+	RepeatBusinessDayOfMonth int
+
+	// Holidays a Calendar of days which are not business days when using
+	// RepeatBusinessDaily or RepeatBusinessDayOfMonth. A day with any part
+	// covered by an Event of the Calendar, which is not canceled, is a holiday.
+	Holidays *Calendar
+
+	// Anchor derives the occurrences from another Rule of the Calendar
+	// instead of repeating the Event. Anchored Rules are only expanded by
+	// Calendar.View, Rule.Expand does not have the other Rules so it does not
+	// return any Events for them.
+	Anchor *Anchor
+
 	// RepeatForwardUntil the time at which the event should last be repeated
 	// when repeating for future events(after the original Event.Start).
 	// If an Event's Start time is equal to this then the Event will be valid
@@ -88,110 +335,624 @@ type Rule struct {
 	Skip []time.Time
 	// Canceled contains a list of times where the Event will be repeated but
 	// marked as cancled. If the time is within the Start and End times of the
-	// Event it will be skipped.
+	// Event it will be marked with StatusCanceled.
 	Canceled []time.Time
+
+	// Overrides contains replacements for single occurrences of the Rule, for
+	// example when one meeting of a series is moved or renamed. Each override
+	// replaces the occurrence which originally started at its RecurrenceID.
+	// Zero value fields of the override, including the Payload, are taken
+	// from the original occurrence, so an override with only a Name renames
+	// the occurrence and one with only a Start moves it while keeping its
+	// duration.
+	//
+	// Overrides for occurrences which are not generated by the Rule, or have
+	// been skipped, are ignored.
+	Overrides []EventOf[T]
 }
 
+// Rule a RuleOf Events without a Payload.
+type Rule = RuleOf[struct{}]
+
 // Expand creates events based on the original event by applying the repeating pattern.
-func (r Rule) Expand(viewStart, viewEnd time.Time) []Event {
-	var expandedEvents []Event
-	// go backwards to the viewstart
-	evaluatingEndTime := r.End
-	for evaluatingStartTime := r.Start; evaluatingStartTime.After(viewStart); evaluatingStartTime = evaluatingStartTime.Add(-r.RepeatDuration) {
-		evaluatingEndTime = evaluatingEndTime.Add(-r.RepeatDuration)
-		expandedEvents = append(expandedEvents, Event{
-			Start: evaluatingStartTime,
-			End:   evaluatingEndTime,
-			Name:  r.Name,
-		})
-	}
-
-	// Go forwards to the viewEnd
-	evaluatingEndTime = r.End
-	for evaluatingStartTime := r.Start; evaluatingStartTime.Before(viewEnd); evaluatingStartTime = evaluatingStartTime.Add(r.RepeatDuration) {
-		evaluatingEndTime = evaluatingEndTime.Add(r.RepeatDuration)
-		expandedEvents = append(expandedEvents, Event{
-			Start: evaluatingStartTime,
-			End:   evaluatingEndTime,
-			Name:  r.Name,
-		})
+// Only Events which are active within the timeframe of viewStart(inclusive) and
+// viewEnd(exclusive) are returned, ordered by their Start.
+//
+// When more than one repeat option is set the first one is used, in the order
+// Cron, RepeatBusinessDaily, RepeatBusinessDayOfMonth, RepeatDuration,
+// RepeatDateAnually, RepeatWeekly, RepeatDayOfMonthMonthly, RepeatDaily,
+// RepeatHourly, RepeatMinutely and RepeatEaster. A Rule without any repeat
+// options results in the Event itself.
+func (r RuleOf[T]) Expand(viewStart, viewEnd time.Time) []EventOf[T] {
+	if r.Anchor != nil {
+		return nil
+	}
+	if r.AllDay {
+		return r.expandAllDay(viewStart, viewEnd)
+	}
+
+	return r.expand(viewStart, viewEnd)
+}
+
+// expandAllDay expands an all day Rule using the dates of the occurrences in
+// the location of the Rule's Start and then moves them to the same dates in the
+// location of viewStart.
+func (r RuleOf[T]) expandAllDay(viewStart, viewEnd time.Time) []EventOf[T] {
+	// Locations differ by less than a day so occurrences on the edges of the
+	// view may be found within a day on either side of it
+	const day = 24 * time.Hour
+
+	var expandedEvents []EventOf[T]
+	for _, occurrence := range r.expand(viewStart.Add(-day), viewEnd.Add(day)) {
+		occurrence = occurrence.datesIn(viewStart.Location())
+		if isInView(occurrence, viewStart, viewEnd) {
+			expandedEvents = append(expandedEvents, occurrence)
+		}
+	}
+
+	return expandedEvents
+}
+
+// datesIn moves an all day Event to midnight on the same dates in the
+// location. A End which is not at midnight includes the rest of that day and
+// the Event always covers at least one day.
+func (e EventOf[T]) datesIn(loc *time.Location) EventOf[T] {
+	start := time.Date(e.Start.Year(), e.Start.Month(), e.Start.Day(), 0, 0, 0, 0, loc)
+	end := time.Date(e.End.Year(), e.End.Month(), e.End.Day(), 0, 0, 0, 0, loc)
+	if !e.End.Equal(startOfDay(e.End)) {
+		end = end.AddDate(0, 0, 1)
+	}
+	if !end.After(start) {
+		end = start.AddDate(0, 0, 1)
+	}
+
+	if e.IsOpenEnded() {
+		end = time.Time{}
+	}
+
+	e.Start, e.End = start, end
+	return e
+}
+
+// expand creates the events of a Rule which is not all day.
+func (r RuleOf[T]) expand(viewStart, viewEnd time.Time) []EventOf[T] {
+	var expandedEvents []EventOf[T]
+	for _, occurrence := range r.occurrences(viewStart, viewEnd) {
+		if _, overridden := r.override(occurrence.Start); overridden {
+			// Overridden occurrences may be moved into or out of the view so
+			// they are handled separately below.
+			continue
+		}
+		expandedEvents = append(expandedEvents, occurrence)
 	}
 
+	for _, override := range r.Overrides {
+		occurrence, ok := r.occurrenceAt(override.RecurrenceID)
+		if !ok {
+			continue
+		}
+
+		event := applyOverride(occurrence, override)
+		if isInView(event, viewStart, viewEnd) {
+			expandedEvents = append(expandedEvents, event)
+		}
+	}
+
+	slices.SortStableFunc(expandedEvents, func(a, b EventOf[T]) int {
+		return a.Start.Compare(b.Start)
+	})
+
 	return expandedEvents
 }
 
+// occurrences generates the occurrences of the Rule which are active within
+// the timeframe without applying any overrides. Skipped occurrences are not
+// included and canceled ones are marked as such.
+func (r RuleOf[T]) occurrences(viewStart, viewEnd time.Time) []EventOf[T] {
+	if r.Cron != "" {
+		return r.cronOccurrences(viewStart, viewEnd)
+	}
+	if r.RepeatBusinessDaily > 0 || r.RepeatBusinessDayOfMonth != 0 {
+		return r.businessOccurrences(viewStart, viewEnd)
+	}
+
+	period := r.period()
+	if period <= 0 {
+		// Not repeating, the Event is the only occurrence
+		occurrence, ok := r.occurrence(0)
+		if !ok || !isInView(occurrence, viewStart, viewEnd) {
+			return nil
+		}
+		return []EventOf[T]{occurrence}
+	}
+
+	// Estimate the occurrence closest to the view and then adjust so n is the
+	// first occurrence which is active after the view starts.
+	n := int(viewStart.Sub(r.Start) / period)
+	for r.isActiveFrom(n-1, viewStart) {
+		n--
+	}
+	for !r.isActiveFrom(n, viewStart) {
+		n++
+	}
+
+	var occurrences []EventOf[T]
+	for ; ; n++ {
+		start, _ := r.repeat(n)
+		if !start.Before(viewEnd) {
+			break
+		}
+		if n > 0 && !r.RepeatForwardUntil.IsZero() && start.After(r.RepeatForwardUntil) {
+			break
+		}
+		if n < 0 && !r.RepeatBackwardUntil.IsZero() && start.Before(r.RepeatBackwardUntil) {
+			continue
+		}
+
+		if !r.matchesFilters(start) {
+			n += r.skipFiltered(start)
+			continue
+		}
+
+		occurrence, ok := r.occurrence(n)
+		if !ok || !isInView(occurrence, viewStart, viewEnd) {
+			continue
+		}
+		occurrences = append(occurrences, occurrence)
+	}
+
+	return occurrences
+}
+
+// isActiveFrom determines if the nth occurrence of the Rule is active at or
+// after t. Open ended occurrences of a repeating Rule are replaced by the next
+// occurrence so they are only active until it starts.
+func (r RuleOf[T]) isActiveFrom(n int, t time.Time) bool {
+	start, end := r.repeat(n)
+	occurrence := EventOf[T]{Start: start, End: end}
+	switch {
+	case occurrence.IsOpenEnded():
+		next, _ := r.repeat(n + 1)
+		return next.After(t)
+	case occurrence.IsInstant():
+		return !start.Before(t)
+	}
+
+	return end.After(t)
+}
+
+// occurrence creates the nth occurrence of the Rule, where the original Event
+// is the 0th. If the occurrence is skipped or filtered out false is returned.
+func (r RuleOf[T]) occurrence(n int) (EventOf[T], bool) {
+	if r.isMissingClock(n) {
+		return EventOf[T]{}, false
+	}
+	return r.occurrenceBetween(r.repeat(n))
+}
+
+// event the Event being repeated with the Payload of the Rule.
+func (r RuleOf[T]) event() EventOf[T] {
+	return withPayload(r.Event, r.Payload)
+}
+
+// occurrenceBetween creates an occurrence of the Rule with the given Start and
+// End times. If the occurrence is skipped or filtered out false is returned.
+func (r RuleOf[T]) occurrenceBetween(start, end time.Time) (EventOf[T], bool) {
+	occurrence := r.event()
+	occurrence.Start, occurrence.End = start, end
+	occurrence.RecurrenceID = occurrence.Start
+	occurrence.RuleID = r.ID
+
+	if !r.matchesFilters(occurrence.Start) || slices.ContainsFunc(r.Skip, occurrence.contains) {
+		return EventOf[T]{}, false
+	}
+	if slices.ContainsFunc(r.Canceled, occurrence.contains) {
+		occurrence.Status = StatusCanceled
+	}
+
+	return occurrence, true
+}
+
+// occurrenceAt finds the occurrence of the Rule which originally starts at the
+// given time, if there is one.
+func (r RuleOf[T]) occurrenceAt(t time.Time) (EventOf[T], bool) {
+	for _, occurrence := range r.occurrences(t, t.Add(time.Nanosecond)) {
+		if occurrence.Start.Equal(t) {
+			return occurrence, true
+		}
+	}
+
+	return EventOf[T]{}, false
+}
+
+// override finds the override for the occurrence which originally starts at
+// the given time.
+func (r RuleOf[T]) override(recurrenceID time.Time) (EventOf[T], bool) {
+	for _, override := range r.Overrides {
+		if override.RecurrenceID.Equal(recurrenceID) {
+			return override, true
+		}
+	}
+
+	return EventOf[T]{}, false
+}
+
+// repeat calculates the Start and End times of the nth occurrence of the Rule.
+// Calendar based repeats use the calendar date so the time of day is
+// preserved across daylight saving time changes and months of different
+// lengths. Occurrences of an open ended Rule are open ended.
+func (r RuleOf[T]) repeat(n int) (time.Time, time.Time) {
+	start, end := r.repeatTimes(n)
+	if r.IsOpenEnded() {
+		end = time.Time{}
+	}
+
+	return start, end
+}
+
+// endFor the End of an occurrence of the Rule starting at the given time, which
+// lasts as long as the original Event.
+func (r RuleOf[T]) endFor(start time.Time) time.Time {
+	if r.IsOpenEnded() {
+		return time.Time{}
+	}
+
+	return start.Add(r.End.Sub(r.Start))
+}
+
+func (r RuleOf[T]) repeatTimes(n int) (time.Time, time.Time) {
+	switch {
+	case r.RepeatDuration > 0:
+		offset := time.Duration(n) * r.RepeatDuration
+		return r.Start.Add(offset), r.End.Add(offset)
+	case r.RepeatDateAnually > 0:
+		return r.Start.AddDate(n*r.RepeatDateAnually, 0, 0), r.End.AddDate(n*r.RepeatDateAnually, 0, 0)
+	case r.RepeatWeekly > 0:
+		return r.Start.AddDate(0, 0, 7*n*r.RepeatWeekly), r.End.AddDate(0, 0, 7*n*r.RepeatWeekly)
+	case r.RepeatDayOfMonthMonthly > 0:
+		return r.Start.AddDate(0, n*r.RepeatDayOfMonthMonthly, 0), r.End.AddDate(0, n*r.RepeatDayOfMonthMonthly, 0)
+	case r.RepeatDaily > 0:
+		return r.Start.AddDate(0, 0, n*r.RepeatDaily), r.End.AddDate(0, 0, n*r.RepeatDaily)
+	case r.RepeatHourly > 0:
+		return addClock(r.Start, n*r.RepeatHourly, 0), addClock(r.End, n*r.RepeatHourly, 0)
+	case r.RepeatMinutely > 0:
+		return addClock(r.Start, 0, n*r.RepeatMinutely), addClock(r.End, 0, n*r.RepeatMinutely)
+	case r.RepeatEaster != ComputusNone:
+		return r.repeatEaster(n)
+	}
+
+	return r.Start, r.End
+}
+
+// addClock adds hours and minutes to the time of day of t, like AddDate does
+// for dates, so the result has the expected time of day when daylight saving
+// time starts or ends in between.
+func addClock(t time.Time, hours, minutes int) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+hours, t.Minute()+minutes, t.Second(), t.Nanosecond(), t.Location())
+}
+
+// isMissingClock determines if the nth occurrence of a Rule repeating hourly
+// or minutely falls on a time of day which does not exist because daylight
+// saving time starts, such as 02:30 in America/New_York. time.Date moves these
+// onto another time of day, which can be another occurrence, so like cron
+// they are not repeated.
+func (r RuleOf[T]) isMissingClock(n int) bool {
+	var hours, minutes int
+	switch {
+	case r.RepeatDuration > 0 || r.RepeatDateAnually > 0 || r.RepeatWeekly > 0 || r.RepeatDayOfMonthMonthly > 0 || r.RepeatDaily > 0:
+		return false
+	case r.RepeatHourly > 0:
+		hours = n * r.RepeatHourly
+	case r.RepeatMinutely > 0:
+		minutes = n * r.RepeatMinutely
+	default:
+		return false
+	}
+
+	// UTC has no daylight saving time so it has the expected time of day
+	start := addClock(r.Start, hours, minutes)
+	expected := addClock(time.Date(r.Start.Year(), r.Start.Month(), r.Start.Day(), r.Start.Hour(), r.Start.Minute(), r.Start.Second(), r.Start.Nanosecond(), time.UTC), hours, minutes)
+	return start.Hour() != expected.Hour() || start.Minute() != expected.Minute()
+}
+
+// matchesFilters determines if an occurrence starting at the given time is
+// allowed by ByHour and ByMinute.
+func (r RuleOf[T]) matchesFilters(start time.Time) bool {
+	if len(r.ByHour) > 0 && !slices.Contains(r.ByHour, start.Hour()) {
+		return false
+	}
+	if len(r.ByMinute) > 0 && !slices.Contains(r.ByMinute, start.Minute()) {
+		return false
+	}
+
+	return true
+}
+
+// skipFiltered the number of additional occurrences which can be skipped
+// after an occurrence starting at the given time was filtered out by ByHour.
+// Repeating every few minutes would otherwise step through every minute of
+// each hour which is filtered out.
+func (r RuleOf[T]) skipFiltered(start time.Time) int {
+	if r.RepeatMinutely <= 0 || r.period() != time.Duration(r.RepeatMinutely)*time.Minute {
+		return 0
+	}
+	if len(r.ByHour) == 0 || slices.Contains(r.ByHour, start.Hour()) {
+		return 0
+	}
+
+	// Occurrences before the next hour are also filtered out
+	minutesLeft := 60 - start.Minute()
+	return (minutesLeft - 1) / r.RepeatMinutely
+}
+
+// period the approximate amount of time between occurrences of the Rule, or 0
+// if the Rule does not repeat.
+func (r RuleOf[T]) period() time.Duration {
+	const day = 24 * time.Hour
+	switch {
+	case r.RepeatDuration > 0:
+		return r.RepeatDuration
+	case r.RepeatDateAnually > 0:
+		return time.Duration(r.RepeatDateAnually) * 365 * day
+	case r.RepeatWeekly > 0:
+		return time.Duration(r.RepeatWeekly) * 7 * day
+	case r.RepeatDayOfMonthMonthly > 0:
+		return time.Duration(r.RepeatDayOfMonthMonthly) * 30 * day
+	case r.RepeatDaily > 0:
+		return time.Duration(r.RepeatDaily) * day
+	case r.RepeatHourly > 0:
+		return time.Duration(r.RepeatHourly) * time.Hour
+	case r.RepeatMinutely > 0:
+		return time.Duration(r.RepeatMinutely) * time.Minute
+	case r.RepeatEaster != ComputusNone:
+		return 365 * day
+	}
+
+	return 0
+}
+
+// applyOverride replaces the fields of the occurrence with the non zero fields
+// of the override.
+func applyOverride[T any](occurrence, override EventOf[T]) EventOf[T] {
+	event := occurrence
+	if !override.Start.IsZero() {
+		event.Start = override.Start
+		if !occurrence.IsOpenEnded() {
+			event.End = override.Start.Add(occurrence.End.Sub(occurrence.Start))
+		}
+	}
+	if !override.End.IsZero() {
+		event.End = override.End
+	}
+	if override.Name != "" {
+		event.Name = override.Name
+	}
+	if override.Status != StatusConfirmed {
+		event.Status = override.Status
+	}
+	if override.Metadata != nil {
+		event.Metadata = override.Metadata
+	}
+	if !reflect.ValueOf(&override.Payload).Elem().IsZero() {
+		event.Payload = override.Payload
+	}
+
+	return event
+}
+
+// contains determines if the time is within the Start(inclusive) and
+// End(exclusive) of the Event. An instant only contains its Start.
+func (e EventOf[T]) contains(t time.Time) bool {
+	if e.IsInstant() {
+		return t.Equal(e.Start)
+	}
+	return !t.Before(e.Start) && t.Before(e.effectiveEnd())
+}
+
+// isInView determines if the Event is active at any point within the timeframe
+// of viewStart(inclusive) and viewEnd(exclusive).
+func isInView[T any](e EventOf[T], viewStart, viewEnd time.Time) bool {
+	if e.IsInstant() {
+		return !e.Start.Before(viewStart) && e.Start.Before(viewEnd)
+	}
+	return e.Start.Before(viewEnd) && e.effectiveEnd().After(viewStart)
+}
+
 // View returns Events that are within the Calendar for the given timeframe.
 // The Rules will be applied to expand repeating Events as well as skipping,
-// canceling, etc.
-func (c *Calendar) View(viewStart, viewEnd time.Time) ([]Event, error) {
-	// 1. Get events that apply to the time view we are interested in
-	// 1a. expand events(recurring events are expanded to specific events within a time span)
-	// 2. order them(may need to think about adding priority here or ensuring we perserve order)
-	// 3. Start with the beginning of the day and generate a consolidated report
-
-	var results []Event
-	for _, rule := range c.Entries {
-		expandedEvents := rule.Expand(viewStart, viewEnd)
+// canceling, etc. Canceled Events do not take up any time so they are not
+// included in the View.
+//
+// An error is returned when a Rule has an Anchor which can not be resolved.
+func (c *CalendarOf[T]) View(viewStart, viewEnd time.Time) ([]EventOf[T], error) {
+	expandedEvents, err := c.expandEntries(viewStart, viewEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.condense(expandedEvents, viewStart, viewEnd), nil
+}
+
+// Expand returns the Events of all the Rules of the Calendar which are
+// within the timeframe of viewStart(inclusive) and viewEnd(exclusive) without
+// condensing them, so Events may overlap. Canceled Events are not included and
+// the Events are ordered by their Start.
+//
+// An error is returned when a Rule has an Anchor which can not be resolved.
+func (c *CalendarOf[T]) Expand(viewStart, viewEnd time.Time) ([]EventOf[T], error) {
+	expandedEvents, err := c.expandEntries(viewStart, viewEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortStableFunc(expandedEvents, func(a, b EventOf[T]) int {
+		return a.Start.Compare(b.Start)
+	})
+
+	return expandedEvents, nil
+}
+
+// expandEntries expands all the Rules of the Calendar, in the order of the
+// Rules, without the canceled Events.
+func (c *CalendarOf[T]) expandEntries(viewStart, viewEnd time.Time) ([]EventOf[T], error) {
+	var results []EventOf[T]
+	for i := range c.Entries {
+		expandedEvents, err := c.expandRule(i, viewStart, viewEnd)
+		if err != nil {
+			return nil, err
+		}
+
 		for _, expandedEvent := range expandedEvents {
-			if (expandedEvent.Start.After(viewStart) || expandedEvent.Start.Equal(viewStart)) || (expandedEvent.End.Before(viewEnd) || expandedEvent.End.Equal(viewEnd)) {
-				// TODO: Filter out events that are not active in this view
+			if expandedEvent.Status == StatusCanceled {
 				continue
 			}
+			results = append(results, expandedEvent)
+		}
+	}
+
+	return results, nil
+}
+
+// condense removes the overlaps of the expanded Events, favoring later ones,
+// and then applies the Merge, MinimumDuration and Idle settings of the
+// Calendar.
+func (c *CalendarOf[T]) condense(expandedEvents []EventOf[T], viewStart, viewEnd time.Time) []EventOf[T] {
+	fragments := reduceFragments(expandedEvents)
+
+	fragmentCounts := map[int]int{}
+	for _, fragment := range fragments {
+		fragmentCounts[fragment.source]++
+	}
+
+	var events []EventOf[T]
+	for _, fragment := range fragments {
+		event, original := fragment.event, expandedEvents[fragment.source]
+		event.Truncated = !event.Start.Equal(original.Start) || !event.End.Equal(original.End)
+		event.Split = fragmentCounts[fragment.source] > 1
+		events = append(events, event)
+	}
+
+	if c.MinimumDuration > 0 {
+		events = removeShortEvents(events, c.MinimumDuration, c.AbsorbShortEvents)
+	}
+	if c.Merge != MergeNone {
+		events = mergeEvents(events, c.Merge)
+	}
+	if c.Idle != nil {
+		events = fillGaps(events, *c.Idle, viewStart, viewEnd)
+	}
+
+	return events
+}
+
+// AssignIDs gives every Rule of the Calendar without an ID one derived from
+// the contents of the Rule, so the same Rules are given the same IDs every
+// time. Rules with the same contents are told apart by a suffix with their
+// count, such as "-2". Rules which already have an ID are not changed.
+func (c *CalendarOf[T]) AssignIDs() {
+	used := map[string]bool{}
+	for _, rule := range c.Entries {
+		used[rule.ID] = true
+	}
+
+	for i := range c.Entries {
+		if c.Entries[i].ID != "" {
+			continue
 		}
 
-		results = append(results, expandedEvents...)
+		c.Entries[i].ID = uniqueID(c.Entries[i].contentID(i), used)
+	}
+}
+
+// uniqueID the base ID, with a number added when it is already used, which is
+// then marked as used.
+func uniqueID(base string, used map[string]bool) string {
+	id := base
+	for n := 2; used[id]; n++ {
+		id = fmt.Sprintf("%s-%d", base, n)
 	}
+	used[id] = true
 
-	// Remove overlaps favoring later events
-	results, err := ReduceAllEvents(results)
+	return id
+}
+
+// contentID an ID derived from the JSON of the Rule, or from its index when
+// it can not be written as JSON.
+func (r RuleOf[T]) contentID(index int) string {
+	data, err := json.Marshal(r)
 	if err != nil {
-		return nil, err
+		data = []byte(fmt.Sprintf("rule %d", index))
 	}
 
-	return results, nil
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:16])
+}
+
+// At returns the Event of the Calendar which is in effect at t, after the
+// overlaps have been removed like View. Instants are not in effect at any time
+// so they are never returned. When there is no Event at t false is returned.
+func (c *CalendarOf[T]) At(t time.Time) (EventOf[T], bool, error) {
+	events, err := c.View(t, t.Add(time.Nanosecond))
+	if err != nil {
+		return EventOf[T]{}, false, err
+	}
+
+	for _, event := range events {
+		if !event.IsInstant() && event.contains(t) {
+			return event, true, nil
+		}
+	}
+
+	return EventOf[T]{}, false, nil
 }
 
-// ReduceAllEvents like reduceEvents but operates on a any number of Events
-func ReduceAllEvents(events []Event) ([]Event, error) {
+// ReduceAllEvents like reduceEvents but operates on a any number of Events.
+// Events later in the slice take precedence over earlier ones and the
+// resulting Events are ordered by their Start.
+func ReduceAllEvents[T any](events []EventOf[T]) ([]EventOf[T], error) {
 	if len(events) < 2 {
 		// 0 or 1 events cannot have any overlaps
 		return events, nil
 	}
 
-	processedEvents := events
+	var reducedEvents []EventOf[T]
+	for _, fragment := range reduceFragments(events) {
+		reducedEvents = append(reducedEvents, fragment.event)
+	}
 
-	i := 0
-	j := 1
-	for j != len(processedEvents) {
+	return reducedEvents, nil
+}
 
-		// Ensure the indexes loop and end correctly.
-		// We can just increment i for simplicity and it will be adjusted here
-		if i == len(processedEvents) {
-			j++
-			i = 0
-		}
-		if i == len(processedEvents)-2 && j == len(processedEvents) {
-			// We are done
-			break
-		}
+// fragment an Event which remains after reducing along with the index of the
+// Event it was derived from.
+type fragment[T any] struct {
+	event  EventOf[T]
+	source int
+}
 
-		if !isOverlap(processedEvents[i], processedEvents[j]) {
-			i++
-			continue
+// reduceFragments does the work of ReduceAllEvents while keeping track of
+// which Event each of the resulting Events was derived from.
+func reduceFragments[T any](events []EventOf[T]) []fragment[T] {
+	var processedFragments []fragment[T]
+	for i, event := range events {
+		// Every Event processed so far has a lower priority than this one so
+		// only what is left of them after reducing is kept.
+		var remainingFragments []fragment[T]
+		for _, processedFragment := range processedFragments {
+			updatedEvents, _ := reduceEvents(processedFragment.event, event)
+			for _, updatedEvent := range updatedEvents {
+				remainingFragments = append(remainingFragments, fragment[T]{event: updatedEvent, source: processedFragment.source})
+			}
 		}
 
-		updatedEvents1, updatedEvents2 := reduceEvents(processedEvents[i], processedEvents[j])
-
-		// Replace original Events with updated versions and rerun processing
-		processedEvents = slices.Replace(processedEvents, i, i+1, updatedEvents1...)
-		processedEvents = slices.Replace(processedEvents, j+len(updatedEvents1), j+len(updatedEvents1)+1, updatedEvents2...)
-
-		i = 0
-		j = 1
-		continue
+		processedFragments = append(remainingFragments, fragment[T]{event: event, source: i})
 	}
 
-	return processedEvents, nil
+	slices.SortStableFunc(processedFragments, func(a, b fragment[T]) int {
+		return a.event.Start.Compare(b.event.Start)
+	})
+
+	return processedFragments
 }
 
 // reduceEvents takes 2 events that may or may not overlap and reutrns a list
@@ -204,14 +965,41 @@ func ReduceAllEvents(events []Event) ([]Event, error) {
 // have one event at any given point in time. Events that are later in the
 // group are given precendence over earlier ones with the idea that later
 // events were created with the previous in mind.
-func reduceEvents(e1 Event, e2 Event) ([]Event, []Event) {
+//
+// Open ended Events are treated as ending at the end of time, so an open ended
+// Event with a lower priority resumes after the higher priority Event ends.
+// Instants do not overlap other Events so they are never removed.
+func reduceEvents[T any](e1 EventOf[T], e2 EventOf[T]) ([]EventOf[T], []EventOf[T]) {
 	if !isOverlap(e1, e2) {
-		return []Event{e1}, []Event{e2}
+		return []EventOf[T]{e1}, []EventOf[T]{e2}
 	}
 
+	updatedEvents1, updatedEvents2 := reduceBoundedEvents(withEnd(e1), withEnd(e2))
+	return withOpenEnds(updatedEvents1), withOpenEnds(updatedEvents2)
+}
+
+// withEnd replaces the End of an open ended Event with the end of time.
+func withEnd[T any](e EventOf[T]) EventOf[T] {
+	e.End = e.effectiveEnd()
+	return e
+}
+
+// withOpenEnds restores the End of Events which end at the end of time.
+func withOpenEnds[T any](events []EventOf[T]) []EventOf[T] {
+	for i := range events {
+		if events[i].End.Equal(endOfTime) {
+			events[i].End = time.Time{}
+		}
+	}
+	return events
+}
+
+// reduceBoundedEvents does the work of reduceEvents for overlapping Events
+// which both have an End.
+func reduceBoundedEvents[T any](e1 EventOf[T], e2 EventOf[T]) ([]EventOf[T], []EventOf[T]) {
 	// Same time span
 	if e1.Start.Equal(e2.Start) && e1.End.Equal(e2.End) {
-		return []Event{}, []Event{e2}
+		return []EventOf[T]{}, []EventOf[T]{e2}
 	}
 
 	// Same Start different end
@@ -219,7 +1007,7 @@ func reduceEvents(e1 Event, e2 Event) ([]Event, []Event) {
 	// |------e1---------|
 	if e1.Start.Equal(e2.Start) && e1.End.After(e2.End) {
 		e1.Start = e2.End
-		return []Event{e1}, []Event{e2}
+		return []EventOf[T]{e1}, []EventOf[T]{e2}
 	}
 
 	// Same Start different end
@@ -227,7 +1015,22 @@ func reduceEvents(e1 Event, e2 Event) ([]Event, []Event) {
 	// |------e1-------|
 	if e1.Start.Equal(e2.Start) && e1.End.Before(e2.End) {
 		e1.Start = e2.End
-		return []Event{}, []Event{e2}
+		return []EventOf[T]{}, []EventOf[T]{e2}
+	}
+
+	// Same End different start
+	//      |-----e2-----|
+	// |------e1---------|
+	if e1.End.Equal(e2.End) && e1.Start.Before(e2.Start) {
+		e1.End = e2.Start
+		return []EventOf[T]{e1}, []EventOf[T]{e2}
+	}
+
+	// Same End different start
+	// |-------e2----------|
+	//     |------e1-------|
+	if e1.End.Equal(e2.End) && e1.Start.After(e2.Start) {
+		return []EventOf[T]{}, []EventOf[T]{e2}
 	}
 
 	// e2 is within e1
@@ -245,7 +1048,7 @@ func reduceEvents(e1 Event, e2 Event) ([]Event, []Event) {
 		e1p2 := e1
 		e1p2.Start = e2.End
 		e1p2.End = e1.End
-		return []Event{e1p1, e1p2}, []Event{e2} // Keep e2 later so it retains its priority over e1
+		return []EventOf[T]{e1p1, e1p2}, []EventOf[T]{e2} // Keep e2 later so it retains its priority over e1
 	}
 
 	// e1 is within e2
@@ -256,7 +1059,7 @@ func reduceEvents(e1 Event, e2 Event) ([]Event, []Event) {
 	// Result
 	// |--------------e2-------------|
 	if e2.Start.Before(e1.Start) && e2.End.After(e1.End) {
-		return []Event{}, []Event{e2}
+		return []EventOf[T]{}, []EventOf[T]{e2}
 	}
 
 	// middle overlap
@@ -268,7 +1071,7 @@ func reduceEvents(e1 Event, e2 Event) ([]Event, []Event) {
 	if e1.Start.Before(e2.Start) && e2.Start.Before(e1.End) && e2.End.After(e1.End) {
 		e1p1 := e1
 		e1p1.End = e2.Start
-		return []Event{e1p1}, []Event{e2}
+		return []EventOf[T]{e1p1}, []EventOf[T]{e2}
 	}
 
 	// middle overlap
@@ -280,26 +1083,32 @@ func reduceEvents(e1 Event, e2 Event) ([]Event, []Event) {
 	if e2.Start.Before(e1.Start) && e1.Start.Before(e2.End) && e1.End.After(e2.End) {
 		e1p1 := e1
 		e1p1.Start = e2.End
-		return []Event{e1p1}, []Event{e2}
+		return []EventOf[T]{e1p1}, []EventOf[T]{e2}
 	}
 
 	panic(fmt.Sprintf("missed something here: %+v, %+v", e1, e2))
 }
 
 // isOverlap determines if the specified Events have any point in time where both are "active".
-func isOverlap(e1 Event, e2 Event) bool {
+// Instants do not take up any time so they never overlap.
+func isOverlap[T any](e1 EventOf[T], e2 EventOf[T]) bool {
+	if e1.IsInstant() || e2.IsInstant() {
+		return false
+	}
+	e1, e2 = withEnd(e1), withEnd(e2)
+
 	// No overlap
 	if e1.Start.Before(e2.Start) && e1.End.Before(e2.Start) || e2.Start.Before(e1.Start) && e2.End.Before(e1.Start) {
 		return false
 	}
 
-	// no overlap matching start and end times
+	// no overlap matching start and end times, in either order
 	// Higher priority up top
 	//               |---------e2------|
 	// |------e1-----|
 	// Result
 	// |-------e1----|--------e2-------|
-	if e1.Start.Before(e2.Start) && e2.Start.Equal(e1.End) && e2.End.After(e1.End) {
+	if e2.Start.Equal(e1.End) || e1.Start.Equal(e2.End) {
 		return false
 	}
 
@@ -32,6 +32,48 @@ func TestExpandEvents(t *testing.T) {
 			},
 			viewStart: time.Date(2020, 2, 13, 0, 0, 0, 0, time.UTC),
 			viewEnd:   time.Date(2025, 2, 13, 0, 0, 0, 0, time.UTC),
+			verificationFunc: func(t *testing.T, e []Event) {
+				// A year is not always 365 days, the occurrences start a day
+				// earlier after February 29th of 2020 and 2024
+				expectedStarts := []time.Time{
+					time.Date(2020, time.February, 13, 0, 0, 0, 0, time.UTC),
+					time.Date(2021, time.February, 12, 0, 0, 0, 0, time.UTC),
+					time.Date(2022, time.February, 12, 0, 0, 0, 0, time.UTC),
+					time.Date(2023, time.February, 12, 0, 0, 0, 0, time.UTC),
+					time.Date(2024, time.February, 12, 0, 0, 0, 0, time.UTC),
+					time.Date(2025, time.February, 11, 0, 0, 0, 0, time.UTC),
+				}
+				if len(e) != len(expectedStarts) {
+					t.Fatalf("Expected %d events but got %d", len(expectedStarts), len(e))
+				}
+
+				for i, evnt := range e {
+					if !evnt.Start.Equal(expectedStarts[i]) {
+						t.Errorf("Expected start to be %s but got %s", expectedStarts[i], evnt.Start)
+					}
+					if evnt.End.Sub(evnt.Start) != 24*time.Hour {
+						t.Errorf("Expected a duration of 24h but got %s", evnt.End.Sub(evnt.Start))
+					}
+				}
+			},
+		},
+		{
+			desc: "Every Year On The Same Date",
+			rule: Rule{
+				Event: Event{
+					Start: time.Date(2020, time.February, 13, 0, 0, 0, 0, time.UTC),
+					End:   time.Date(2020, time.February, 14, 0, 0, 0, 0, time.UTC),
+					Name:  "Dominico's Birthday",
+				},
+				// A 365 day RepeatDuration would drift a day after every leap year
+				RepeatDateAnually:   1,
+				RepeatForwardUntil:  time.Date(2025, 2, 13, 0, 0, 0, 0, time.UTC),
+				RepeatBackwardUntil: time.Time{},
+				Skip:                []time.Time{},
+				Canceled:            []time.Time{},
+			},
+			viewStart: time.Date(2020, 2, 13, 0, 0, 0, 0, time.UTC),
+			viewEnd:   time.Date(2025, 2, 13, 0, 0, 0, 0, time.UTC),
 			verificationFunc: func(t *testing.T, e []Event) {
 				if len(e) != 5 {
 					t.Logf("Expected 5 events but got %d", len(e))
@@ -60,6 +102,208 @@ func TestExpandEvents(t *testing.T) {
 				}
 			},
 		},
+		{
+			desc: "Weekly With Skip And Canceled",
+			rule: Rule{
+				Event: Event{
+					Start: time.Date(2024, time.March, 4, 9, 0, 0, 0, time.UTC),
+					End:   time.Date(2024, time.March, 4, 10, 0, 0, 0, time.UTC),
+					Name:  "Standup",
+				},
+				RepeatWeekly: 1,
+				Skip:         []time.Time{time.Date(2024, time.March, 11, 9, 30, 0, 0, time.UTC)},
+				Canceled:     []time.Time{time.Date(2024, time.March, 18, 9, 0, 0, 0, time.UTC)},
+			},
+			viewStart: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+			viewEnd:   time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC),
+			verificationFunc: func(t *testing.T, e []Event) {
+				expectedDays := []int{4, 18, 25}
+				if len(e) != len(expectedDays) {
+					t.Fatalf("Expected %d events but got %d", len(expectedDays), len(e))
+				}
+
+				for i, evnt := range e {
+					if evnt.Start.Day() != expectedDays[i] {
+						t.Errorf("Expected start day of the month to be %d but got %d", expectedDays[i], evnt.Start.Day())
+					}
+					if !evnt.RecurrenceID.Equal(evnt.Start) {
+						t.Errorf("Expected recurrence id %s to match start %s", evnt.RecurrenceID, evnt.Start)
+					}
+					expectedStatus := StatusConfirmed
+					if evnt.Start.Day() == 18 {
+						expectedStatus = StatusCanceled
+					}
+					if evnt.Status != expectedStatus {
+						t.Errorf("Expected status on day %d to be %d but got %d", evnt.Start.Day(), expectedStatus, evnt.Status)
+					}
+				}
+			},
+		},
+		{
+			desc: "Overrides Move And Rename Occurrences",
+			rule: Rule{
+				Event: Event{
+					Start: time.Date(2024, time.March, 4, 9, 0, 0, 0, time.UTC),
+					End:   time.Date(2024, time.March, 4, 10, 0, 0, 0, time.UTC),
+					Name:  "Standup",
+				},
+				RepeatWeekly: 1,
+				Overrides: []Event{
+					// Moved later in the day
+					{RecurrenceID: time.Date(2024, time.March, 11, 9, 0, 0, 0, time.UTC), Start: time.Date(2024, time.March, 11, 13, 0, 0, 0, time.UTC)},
+					// Renamed
+					{RecurrenceID: time.Date(2024, time.March, 18, 9, 0, 0, 0, time.UTC), Name: "Retro"},
+					// Moved out of the view
+					{RecurrenceID: time.Date(2024, time.March, 25, 9, 0, 0, 0, time.UTC), Start: time.Date(2024, time.April, 2, 9, 0, 0, 0, time.UTC)},
+					// Moved into the view from outside of it
+					{RecurrenceID: time.Date(2024, time.April, 8, 9, 0, 0, 0, time.UTC), Start: time.Date(2024, time.March, 27, 9, 0, 0, 0, time.UTC)},
+					// Not an occurrence of the Rule
+					{RecurrenceID: time.Date(2024, time.March, 5, 9, 0, 0, 0, time.UTC), Start: time.Date(2024, time.March, 6, 9, 0, 0, 0, time.UTC)},
+				},
+			},
+			viewStart: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+			viewEnd:   time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC),
+			verificationFunc: func(t *testing.T, e []Event) {
+				expected := []Event{
+					{Start: time.Date(2024, time.March, 4, 9, 0, 0, 0, time.UTC), End: time.Date(2024, time.March, 4, 10, 0, 0, 0, time.UTC), Name: "Standup", RecurrenceID: time.Date(2024, time.March, 4, 9, 0, 0, 0, time.UTC)},
+					{Start: time.Date(2024, time.March, 11, 13, 0, 0, 0, time.UTC), End: time.Date(2024, time.March, 11, 14, 0, 0, 0, time.UTC), Name: "Standup", RecurrenceID: time.Date(2024, time.March, 11, 9, 0, 0, 0, time.UTC)},
+					{Start: time.Date(2024, time.March, 18, 9, 0, 0, 0, time.UTC), End: time.Date(2024, time.March, 18, 10, 0, 0, 0, time.UTC), Name: "Retro", RecurrenceID: time.Date(2024, time.March, 18, 9, 0, 0, 0, time.UTC)},
+					{Start: time.Date(2024, time.March, 27, 9, 0, 0, 0, time.UTC), End: time.Date(2024, time.March, 27, 10, 0, 0, 0, time.UTC), Name: "Standup", RecurrenceID: time.Date(2024, time.April, 8, 9, 0, 0, 0, time.UTC)},
+				}
+				if !slices.Equal(expected, e) {
+					t.Errorf("Expected %v but got %v", expected, e)
+				}
+			},
+		},
+		{
+			desc: "Every 4 Hours Between 8 And 20 Across Daylight Saving Time",
+			rule: Rule{
+				Event: Event{
+					Start: time.Date(2024, time.March, 9, 8, 0, 0, 0, newYork),
+					End:   time.Date(2024, time.March, 9, 8, 15, 0, 0, newYork),
+					Name:  "Check",
+				},
+				RepeatHourly: 4,
+				ByHour:       []int{8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20},
+			},
+			viewStart: time.Date(2024, time.March, 9, 0, 0, 0, 0, newYork),
+			viewEnd:   time.Date(2024, time.March, 12, 0, 0, 0, 0, newYork),
+			verificationFunc: func(t *testing.T, e []Event) {
+				expectedHours := []int{8, 12, 16, 20}
+				if len(e) != 3*len(expectedHours) {
+					t.Fatalf("Expected %d events but got %d", 3*len(expectedHours), len(e))
+				}
+
+				for i, evnt := range e {
+					if evnt.Start.Day() != 9+i/len(expectedHours) {
+						t.Errorf("Expected start day of the month to be %d but got %d", 9+i/len(expectedHours), evnt.Start.Day())
+					}
+					if evnt.Start.Hour() != expectedHours[i%len(expectedHours)] || evnt.Start.Minute() != 0 {
+						t.Errorf("Expected start time to be %d:00 but got %s", expectedHours[i%len(expectedHours)], evnt.Start)
+					}
+					if evnt.End.Sub(evnt.Start) != 15*time.Minute {
+						t.Errorf("Expected a duration of 15m but got %s", evnt.End.Sub(evnt.Start))
+					}
+				}
+			},
+		},
+		{
+			desc: "Every Hour When Daylight Saving Time Starts",
+			rule: Rule{
+				Event: Event{
+					Start: time.Date(2024, time.March, 10, 0, 0, 0, 0, newYork),
+					End:   time.Date(2024, time.March, 10, 0, 10, 0, 0, newYork),
+					Name:  "Check",
+				},
+				RepeatHourly: 1,
+			},
+			viewStart: time.Date(2024, time.March, 10, 0, 0, 0, 0, newYork),
+			viewEnd:   time.Date(2024, time.March, 10, 5, 0, 0, 0, newYork),
+			verificationFunc: func(t *testing.T, e []Event) {
+				// 02:00 does not exist so there is no occurrence for it
+				expected := []time.Time{
+					time.Date(2024, time.March, 10, 0, 0, 0, 0, newYork),
+					time.Date(2024, time.March, 10, 1, 0, 0, 0, newYork),
+					time.Date(2024, time.March, 10, 3, 0, 0, 0, newYork),
+					time.Date(2024, time.March, 10, 4, 0, 0, 0, newYork),
+				}
+				if len(e) != len(expected) {
+					t.Fatalf("Expected %d events but got %d: %v", len(expected), len(e), e)
+				}
+
+				for i, evnt := range e {
+					if !evnt.Start.Equal(expected[i]) {
+						t.Errorf("Expected start to be %s but got %s", expected[i], evnt.Start)
+					}
+				}
+			},
+		},
+		{
+			desc: "Every 30 Minutes When Daylight Saving Time Starts",
+			rule: Rule{
+				Event: Event{
+					Start: time.Date(2024, time.March, 10, 1, 0, 0, 0, newYork),
+					End:   time.Date(2024, time.March, 10, 1, 5, 0, 0, newYork),
+					Name:  "Poll",
+				},
+				RepeatMinutely: 30,
+			},
+			viewStart: time.Date(2024, time.March, 10, 1, 0, 0, 0, newYork),
+			viewEnd:   time.Date(2024, time.March, 10, 4, 0, 0, 0, newYork),
+			verificationFunc: func(t *testing.T, e []Event) {
+				expected := []time.Time{
+					time.Date(2024, time.March, 10, 1, 0, 0, 0, newYork),
+					time.Date(2024, time.March, 10, 1, 30, 0, 0, newYork),
+					time.Date(2024, time.March, 10, 3, 0, 0, 0, newYork),
+					time.Date(2024, time.March, 10, 3, 30, 0, 0, newYork),
+				}
+				if len(e) != len(expected) {
+					t.Fatalf("Expected %d events but got %d: %v", len(expected), len(e), e)
+				}
+
+				for i, evnt := range e {
+					if !evnt.Start.Equal(expected[i]) {
+						t.Errorf("Expected start to be %s but got %s", expected[i], evnt.Start)
+					}
+				}
+			},
+		},
+		{
+			desc: "Every 20 Minutes At 9 And 17",
+			rule: Rule{
+				Event: Event{
+					Start: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+					End:   time.Date(2024, time.January, 1, 0, 5, 0, 0, time.UTC),
+					Name:  "Poll",
+				},
+				RepeatMinutely: 20,
+				ByHour:         []int{9, 17},
+				ByMinute:       []int{0, 40},
+			},
+			viewStart: time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC),
+			viewEnd:   time.Date(2024, time.June, 3, 0, 0, 0, 0, time.UTC),
+			verificationFunc: func(t *testing.T, e []Event) {
+				expected := []time.Time{
+					time.Date(2024, time.June, 1, 9, 0, 0, 0, time.UTC),
+					time.Date(2024, time.June, 1, 9, 40, 0, 0, time.UTC),
+					time.Date(2024, time.June, 1, 17, 0, 0, 0, time.UTC),
+					time.Date(2024, time.June, 1, 17, 40, 0, 0, time.UTC),
+					time.Date(2024, time.June, 2, 9, 0, 0, 0, time.UTC),
+					time.Date(2024, time.June, 2, 9, 40, 0, 0, time.UTC),
+					time.Date(2024, time.June, 2, 17, 0, 0, 0, time.UTC),
+					time.Date(2024, time.June, 2, 17, 40, 0, 0, time.UTC),
+				}
+				if len(e) != len(expected) {
+					t.Fatalf("Expected %d events but got %d", len(expected), len(e))
+				}
+
+				for i, evnt := range e {
+					if !evnt.Start.Equal(expected[i]) {
+						t.Errorf("Expected start to be %s but got %s", expected[i], evnt.Start)
+					}
+				}
+			},
+		},
 	}
 	for _, tC := range testCases {
 		t.Run(tC.desc, func(t *testing.T) {
@@ -69,6 +313,387 @@ func TestExpandEvents(t *testing.T) {
 	}
 }
 
+func TestCalendarView(t *testing.T) {
+	calendar := Calendar{
+		Name: "Work",
+		Entries: []Rule{
+			{
+				Event: Event{
+					Start: time.Date(2024, time.March, 4, 9, 0, 0, 0, time.UTC),
+					End:   time.Date(2024, time.March, 4, 17, 0, 0, 0, time.UTC),
+					Name:  "Working",
+				},
+				RepeatDaily: 1,
+				Canceled:    []time.Time{time.Date(2024, time.March, 5, 9, 0, 0, 0, time.UTC)},
+			},
+			{
+				Event: Event{
+					Start: time.Date(2024, time.March, 4, 12, 0, 0, 0, time.UTC),
+					End:   time.Date(2024, time.March, 4, 13, 0, 0, 0, time.UTC),
+					Name:  "Lunch",
+				},
+				RepeatDaily: 1,
+				Overrides: []Event{
+					{RecurrenceID: time.Date(2024, time.March, 4, 12, 0, 0, 0, time.UTC), Start: time.Date(2024, time.March, 4, 16, 30, 0, 0, time.UTC)},
+				},
+			},
+		},
+	}
+
+	got, err := calendar.View(time.Date(2024, time.March, 4, 0, 0, 0, 0, time.UTC), time.Date(2024, time.March, 6, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Event{
+		{Start: time.Date(2024, time.March, 4, 9, 0, 0, 0, time.UTC), End: time.Date(2024, time.March, 4, 16, 30, 0, 0, time.UTC), Name: "Working", RecurrenceID: time.Date(2024, time.March, 4, 9, 0, 0, 0, time.UTC), Truncated: true},
+		{Start: time.Date(2024, time.March, 4, 16, 30, 0, 0, time.UTC), End: time.Date(2024, time.March, 4, 17, 30, 0, 0, time.UTC), Name: "Lunch", RecurrenceID: time.Date(2024, time.March, 4, 12, 0, 0, 0, time.UTC)},
+		{Start: time.Date(2024, time.March, 5, 12, 0, 0, 0, time.UTC), End: time.Date(2024, time.March, 5, 13, 0, 0, 0, time.UTC), Name: "Lunch", RecurrenceID: time.Date(2024, time.March, 5, 12, 0, 0, 0, time.UTC)},
+	}
+	if !slices.Equal(expected, got) {
+		t.Errorf("Expected %v but got %v", expected, got)
+	}
+}
+
+func TestAllDayEvents(t *testing.T) {
+	birthday := Rule{
+		Event: Event{
+			Start:  time.Date(2020, time.February, 13, 0, 0, 0, 0, time.UTC),
+			End:    time.Date(2020, time.February, 14, 0, 0, 0, 0, time.UTC),
+			Name:   "Dominico's Birthday",
+			AllDay: true,
+		},
+		RepeatDateAnually: 1,
+	}
+
+	t.Run("Same Dates In Every Location", func(t *testing.T) {
+		for _, loc := range []*time.Location{time.UTC, newYork, time.FixedZone("UTC+14", 14*60*60)} {
+			got := birthday.Expand(time.Date(2024, time.February, 1, 0, 0, 0, 0, loc), time.Date(2024, time.March, 1, 0, 0, 0, 0, loc))
+			expected := []Event{{
+				Start:        time.Date(2024, time.February, 13, 0, 0, 0, 0, loc),
+				End:          time.Date(2024, time.February, 14, 0, 0, 0, 0, loc),
+				Name:         "Dominico's Birthday",
+				AllDay:       true,
+				RecurrenceID: time.Date(2024, time.February, 13, 0, 0, 0, 0, time.UTC),
+			}}
+			if !slices.Equal(expected, got) {
+				t.Errorf("Expected %v but got %v in %s", expected, got, loc)
+			}
+		}
+	})
+
+	t.Run("Precedence By Rule Order", func(t *testing.T) {
+		lunch := Rule{
+			Event: Event{
+				Start: time.Date(2024, time.February, 13, 12, 0, 0, 0, newYork),
+				End:   time.Date(2024, time.February, 13, 13, 0, 0, 0, newYork),
+				Name:  "Lunch",
+			},
+		}
+		viewStart := time.Date(2024, time.February, 13, 0, 0, 0, 0, newYork)
+		viewEnd := time.Date(2024, time.February, 14, 0, 0, 0, 0, newYork)
+
+		// A later timed Event splits the all day Event
+		calendar := Calendar{Entries: []Rule{birthday, lunch}}
+		got, err := calendar.View(viewStart, viewEnd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, e := range got {
+			names = append(names, e.Name)
+		}
+		if expected := []string{"Dominico's Birthday", "Lunch", "Dominico's Birthday"}; !slices.Equal(expected, names) {
+			t.Errorf("Expected %v but got %v", expected, names)
+		}
+
+		// A later all day Event hides the timed Event
+		calendar = Calendar{Entries: []Rule{lunch, birthday}}
+		got, err = calendar.View(viewStart, viewEnd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 1 || got[0].Name != "Dominico's Birthday" || !got[0].Start.Equal(viewStart) || !got[0].End.Equal(viewEnd) {
+			t.Errorf("Expected only the whole day birthday but got %v", got)
+		}
+	})
+}
+
+func TestOpenEndedAndInstantEvents(t *testing.T) {
+	day := func(d, hour int) time.Time {
+		return time.Date(2024, time.March, d, hour, 0, 0, 0, time.UTC)
+	}
+
+	t.Run("Open Ended State Resumes And Instants Are Kept", func(t *testing.T) {
+		calendar := Calendar{
+			Entries: []Rule{
+				{Event: Event{Start: day(1, 0), Name: "Heating Off"}},
+				{Event: Event{Start: day(3, 12), End: day(3, 12), Name: "Deploy"}},
+				{Event: Event{Start: day(3, 9), End: day(3, 17), Name: "Heating On"}},
+			},
+		}
+
+		got, err := calendar.View(day(1, 0), day(5, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := []Event{
+			{Start: day(1, 0), End: day(3, 9), Name: "Heating Off", RecurrenceID: day(1, 0), Truncated: true, Split: true},
+			{Start: day(3, 9), End: day(3, 17), Name: "Heating On", RecurrenceID: day(3, 9)},
+			{Start: day(3, 12), End: day(3, 12), Name: "Deploy", RecurrenceID: day(3, 12)},
+			{Start: day(3, 17), Name: "Heating Off", RecurrenceID: day(1, 0), Truncated: true, Split: true},
+		}
+		if !slices.Equal(expected, got) {
+			t.Errorf("Expected %v but got %v", expected, got)
+		}
+		if !got[3].IsOpenEnded() || !got[2].IsInstant() {
+			t.Errorf("Expected an open ended Event and an instant but got %v", got)
+		}
+	})
+
+	t.Run("Repeating Open Ended Rule", func(t *testing.T) {
+		calendar := Calendar{
+			Entries: []Rule{
+				{Event: Event{Start: day(1, 9), Name: "Mode"}, RepeatDaily: 1},
+			},
+		}
+
+		got, err := calendar.View(day(3, 0), day(5, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := []Event{
+			{Start: day(2, 9), End: day(3, 9), Name: "Mode", RecurrenceID: day(2, 9), Truncated: true},
+			{Start: day(3, 9), End: day(4, 9), Name: "Mode", RecurrenceID: day(3, 9), Truncated: true},
+			{Start: day(4, 9), Name: "Mode", RecurrenceID: day(4, 9)},
+		}
+		if !slices.Equal(expected, got) {
+			t.Errorf("Expected %v but got %v", expected, got)
+		}
+	})
+
+	t.Run("Instants On The Edges Of The View", func(t *testing.T) {
+		alarm := Rule{Event: Event{Start: day(1, 0), End: day(1, 0), Name: "Alarm"}, RepeatDaily: 1}
+
+		got := alarm.Expand(day(3, 0), day(5, 0))
+		if len(got) != 2 || !got[0].Start.Equal(day(3, 0)) || !got[1].Start.Equal(day(4, 0)) {
+			t.Errorf("Expected instants on the 3rd and 4th but got %v", got)
+		}
+	})
+}
+
+func TestEventMetadata(t *testing.T) {
+	standup := &Metadata{
+		Description: "Daily status update",
+		Location:    "Room 1",
+		Tags:        []string{"team", "recurring"},
+		Color:       "#00ff00",
+		Attributes:  map[string]string{"owner": "alex"},
+	}
+	retro := &Metadata{Location: "Room 2", Tags: []string{"team"}}
+
+	calendar := Calendar{
+		Entries: []Rule{
+			{
+				Event: Event{
+					Start:    time.Date(2024, time.March, 4, 9, 0, 0, 0, time.UTC),
+					End:      time.Date(2024, time.March, 4, 10, 0, 0, 0, time.UTC),
+					Name:     "Standup",
+					Metadata: standup,
+				},
+				RepeatDaily: 1,
+				Overrides: []Event{
+					{RecurrenceID: time.Date(2024, time.March, 5, 9, 0, 0, 0, time.UTC), Name: "Retro", Metadata: retro},
+				},
+			},
+			{
+				Event: Event{
+					Start: time.Date(2024, time.March, 4, 9, 15, 0, 0, time.UTC),
+					End:   time.Date(2024, time.March, 4, 9, 30, 0, 0, time.UTC),
+					Name:  "Call",
+				},
+			},
+		},
+	}
+
+	got, err := calendar.View(time.Date(2024, time.March, 4, 0, 0, 0, 0, time.UTC), time.Date(2024, time.March, 6, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []*Metadata{standup, nil, standup, retro}
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %d events but got %v", len(expected), got)
+	}
+	for i, e := range got {
+		if e.Metadata != expected[i] {
+			t.Errorf("Expected %s at %s to have metadata %+v but got %+v", e.Name, e.Start, expected[i], e.Metadata)
+		}
+	}
+	if !got[0].HasTag("recurring") || got[3].HasTag("recurring") || got[1].HasTag("team") {
+		t.Errorf("Expected tags to follow the metadata but got %v", got)
+	}
+}
+
+func TestCalendarOfPayload(t *testing.T) {
+	type setpoint struct {
+		Celsius float64
+	}
+
+	calendar := CalendarOf[setpoint]{
+		Entries: []RuleOf[setpoint]{
+			{
+				Event: Event{
+					Start: time.Date(2024, time.March, 4, 0, 0, 0, 0, time.UTC),
+					Name:  "Eco",
+				},
+				Payload: setpoint{Celsius: 17},
+			},
+			{
+				Event: Event{
+					Start: time.Date(2024, time.March, 4, 7, 0, 0, 0, time.UTC),
+					End:   time.Date(2024, time.March, 4, 22, 0, 0, 0, time.UTC),
+					Name:  "Comfort",
+				},
+				Payload:             setpoint{Celsius: 21},
+				RepeatDaily:         1,
+				RepeatBackwardUntil: time.Date(2024, time.March, 4, 7, 0, 0, 0, time.UTC),
+				Overrides: []EventOf[setpoint]{
+					{RecurrenceID: time.Date(2024, time.March, 5, 7, 0, 0, 0, time.UTC), Payload: setpoint{Celsius: 23}},
+					{RecurrenceID: time.Date(2024, time.March, 6, 7, 0, 0, 0, time.UTC), Start: time.Date(2024, time.March, 6, 9, 0, 0, 0, time.UTC)},
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		desc     string
+		at       time.Time
+		expected setpoint
+		found    bool
+	}{
+		{
+			desc:  "before any event",
+			at:    time.Date(2024, time.March, 3, 12, 0, 0, 0, time.UTC),
+			found: false,
+		},
+		{
+			desc:     "lower priority event",
+			at:       time.Date(2024, time.March, 4, 3, 0, 0, 0, time.UTC),
+			expected: setpoint{Celsius: 17},
+			found:    true,
+		},
+		{
+			desc:     "higher priority event",
+			at:       time.Date(2024, time.March, 4, 7, 0, 0, 0, time.UTC),
+			expected: setpoint{Celsius: 21},
+			found:    true,
+		},
+		{
+			desc:     "lower priority event resumes",
+			at:       time.Date(2024, time.March, 4, 22, 0, 0, 0, time.UTC),
+			expected: setpoint{Celsius: 17},
+			found:    true,
+		},
+		{
+			desc:     "override payload",
+			at:       time.Date(2024, time.March, 5, 12, 0, 0, 0, time.UTC),
+			expected: setpoint{Celsius: 23},
+			found:    true,
+		},
+		{
+			desc:     "override without payload keeps the original",
+			at:       time.Date(2024, time.March, 6, 12, 0, 0, 0, time.UTC),
+			expected: setpoint{Celsius: 21},
+			found:    true,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			got, found, err := calendar.At(tC.at)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if found != tC.found || got.Payload != tC.expected {
+				t.Errorf("Expected %v(%t) at %s but got %v(%t)", tC.expected, tC.found, tC.at, got.Payload, found)
+			}
+		})
+	}
+}
+
+func TestViewProvenance(t *testing.T) {
+	at := func(hour int) time.Time {
+		return time.Date(2024, time.March, 4, hour, 0, 0, 0, time.UTC)
+	}
+
+	calendar := Calendar{
+		Entries: []Rule{
+			{ID: "focus", Event: Event{Start: at(9), End: at(17), Name: "Focus"}},
+			{ID: "standup", Event: Event{Start: at(8), End: at(10), Name: "Standup"}},
+			{ID: "meeting", Event: Event{Start: at(12), End: at(13), Name: "Meeting"}},
+		},
+	}
+
+	got, err := calendar.View(at(0), at(24))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Event{
+		{Start: at(8), End: at(10), Name: "Standup", RecurrenceID: at(8), RuleID: "standup"},
+		{Start: at(10), End: at(12), Name: "Focus", RecurrenceID: at(9), RuleID: "focus", Truncated: true, Split: true},
+		{Start: at(12), End: at(13), Name: "Meeting", RecurrenceID: at(12), RuleID: "meeting"},
+		{Start: at(13), End: at(17), Name: "Focus", RecurrenceID: at(9), RuleID: "focus", Truncated: true, Split: true},
+	}
+	if !slices.Equal(expected, got) {
+		t.Errorf("Expected %v but got %v", expected, got)
+	}
+}
+
+func TestAssignIDs(t *testing.T) {
+	calendar := Calendar{
+		Entries: []Rule{
+			{Event: Event{Name: "First"}},
+			{ID: "second", Event: Event{Name: "Second"}},
+			{Event: Event{Name: "Third"}},
+		},
+	}
+
+	calendar.AssignIDs()
+
+	first, third := calendar.Entries[0].ID, calendar.Entries[2].ID
+	if first == "" || third == "" || first == third {
+		t.Errorf("Expected unique IDs to be assigned but got %q and %q", first, third)
+	}
+	if calendar.Entries[1].ID != "second" {
+		t.Errorf("Expected the existing ID to be kept but got %q", calendar.Entries[1].ID)
+	}
+
+	calendar.AssignIDs()
+	if calendar.Entries[0].ID != first || calendar.Entries[2].ID != third {
+		t.Errorf("Expected assigned IDs to remain the same")
+	}
+
+	// The same Rules are given the same IDs
+	again := Calendar{Entries: []Rule{{Event: Event{Name: "First"}}, {Event: Event{Name: "Third"}}}}
+	again.AssignIDs()
+	if again.Entries[0].ID != first || again.Entries[1].ID != third {
+		t.Errorf("Expected IDs %q and %q but got %q and %q", first, third, again.Entries[0].ID, again.Entries[1].ID)
+	}
+
+	// Rules with the same contents are given different IDs
+	duplicates := Calendar{Entries: []Rule{{Event: Event{Name: "First"}}, {Event: Event{Name: "First"}}}}
+	duplicates.AssignIDs()
+	if expected := []string{first, first + "-2"}; duplicates.Entries[0].ID != expected[0] || duplicates.Entries[1].ID != expected[1] {
+		t.Errorf("Expected IDs %v but got %q and %q", expected, duplicates.Entries[0].ID, duplicates.Entries[1].ID)
+	}
+}
+
+// newYork a location which observes daylight saving time
+var newYork, _ = time.LoadLocation("America/New_York")
+
 // Fixed time that can be used to ensure that fractional seconds are not off causing inconsistent test results
 var rightNow = time.Now().Truncate(time.Millisecond)
 
@@ -130,6 +755,35 @@ func TestSquashEvents(t *testing.T) {
 				return []Event{e1}, []Event{e2}
 			},
 		},
+		{
+			desc: "Open Ended Resumes After",
+			e1:   Event{Name: "one", Start: rightNow},
+			e2:   Event{Name: "two", Start: rightNow.AddDate(0, 0, 1), End: rightNow.AddDate(0, 0, 2)},
+			expectedResult: func(e1, e2 Event) ([]Event, []Event) {
+				before := e1
+				before.End = e2.Start
+				after := e1
+				after.Start = e2.End
+				return []Event{before, after}, []Event{e2}
+			},
+		},
+		{
+			desc: "Open Ended Replaced By Open Ended",
+			e1:   Event{Name: "one", Start: rightNow},
+			e2:   Event{Name: "two", Start: rightNow.AddDate(0, 0, 1)},
+			expectedResult: func(e1, e2 Event) ([]Event, []Event) {
+				e1.End = e2.Start
+				return []Event{e1}, []Event{e2}
+			},
+		},
+		{
+			desc: "Instant Is Kept",
+			e1:   Event{Name: "one", Start: rightNow.AddDate(0, 0, 1), End: rightNow.AddDate(0, 0, 1)},
+			e2:   Event{Name: "two", Start: rightNow, End: rightNow.AddDate(0, 0, 2)},
+			expectedResult: func(e1, e2 Event) ([]Event, []Event) {
+				return []Event{e1}, []Event{e2}
+			},
+		},
 	}
 	for _, tC := range testCases {
 		t.Run(tC.desc, func(t *testing.T) {
@@ -295,6 +949,7 @@ func TestReduceAllEvents(t *testing.T) {
 					{Start: rightNow, End: rightNow.AddDate(0, 0, 1)},
 					{Start: rightNow.AddDate(0, 0, 1), End: rightNow.AddDate(0, 0, 2)},
 					{Start: rightNow.AddDate(0, 0, 2), End: rightNow.AddDate(0, 0, 4)},
+					{Start: rightNow.AddDate(0, 0, 4), End: rightNow.AddDate(0, 0, 5)},
 				}
 			},
 		},
@@ -310,6 +965,7 @@ func TestReduceAllEvents(t *testing.T) {
 					{Start: rightNow, End: rightNow.AddDate(0, 0, 1)},
 					{Start: rightNow.AddDate(0, 0, 1), End: rightNow.AddDate(0, 0, 2)},
 					{Start: rightNow.AddDate(0, 0, 2), End: rightNow.AddDate(0, 0, 4)},
+					{Start: rightNow.AddDate(0, 0, 4), End: rightNow.AddDate(0, 0, 5)},
 				}
 			},
 		},
@@ -0,0 +1,431 @@
+package ephemeris
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseCron creates a Rule which repeats at the times matching the cron
+// expression. Each Event lasts for duration and the expression is matched
+// using the time of day in loc. The Rule repeats without any limits, which can
+// be added with RepeatForwardUntil and RepeatBackwardUntil, and has no Name.
+//
+// The expression uses the standard 5 fields: minute, hour, day of month, month
+// and day of week. Each field can be a *, a value, a range(1-5), a step(*/15
+// or 1-30/5) or a comma separated list of those. Months and days of the week
+// can be given by name(JAN, MON) and Sunday is both 0 and 7. Like other cron
+// implementations when both the day of month and day of week are restricted
+// an Event occurs on days matching either of them.
+//
+// The following extensions are also supported:
+//   - @yearly(@annually), @monthly, @weekly, @daily(@midnight) and @hourly
+//   - L in the day of month for the last day of the month
+//   - W in the day of month for the closest weekday(Monday to Friday) to the
+//     day in the same month, for example 15W, and LW for the last weekday
+//   - L in the day of week for the last of that day in the month, for
+//     example 5L is the last Friday
+//   - # in the day of week for the nth of that day in the month, for example
+//     1#2 is the second Monday
+func ParseCron(expr string, duration time.Duration, loc *time.Location) (Rule, error) {
+	if _, err := parseCron(expr); err != nil {
+		return Rule{}, err
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	// Cron expressions do not have a start so one is picked long before any
+	// Event of interest.
+	start := time.Date(1970, time.January, 1, 0, 0, 0, 0, loc)
+	return Rule{
+		Event: Event{
+			Start: start,
+			End:   start.Add(duration),
+		},
+		Cron: expr,
+	}, nil
+}
+
+// cronSchedule a parsed cron expression. The fields are bit sets where bit n
+// is set when the value n matches.
+type cronSchedule struct {
+	minute, hour, dayOfMonth, month, dayOfWeek uint64
+
+	// dayOfMonthRestricted and dayOfWeekRestricted are false when the field
+	// is *, in which case only the other field is used to match days.
+	dayOfMonthRestricted bool
+	dayOfWeekRestricted  bool
+
+	lastDayOfMonth     bool
+	lastWeekdayOfMonth bool
+	// nearestWeekday days of the month which match their closest weekday.
+	nearestWeekday []int
+	// lastDayOfWeek days of the week which match their last day in the month.
+	lastDayOfWeek []time.Weekday
+	// nthDayOfWeek days of the week which match their nth day in the month.
+	nthDayOfWeek []nthWeekday
+}
+
+type nthWeekday struct {
+	weekday time.Weekday
+	n       int
+}
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var cronMonthNames = map[string]int{
+	"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+	"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+}
+
+var cronDayNames = map[string]int{
+	"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+}
+
+// parseCron parses the cron expression described by ParseCron.
+func parseCron(expr string) (cronSchedule, error) {
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "@") {
+		macro, ok := cronMacros[strings.ToLower(expr)]
+		if !ok {
+			return cronSchedule{}, fmt.Errorf("cron expression %q: unknown macro", expr)
+		}
+		expr = macro
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return cronSchedule{}, fmt.Errorf("cron expression %q: expected 5 fields but got %d", expr, len(fields))
+	}
+
+	var schedule cronSchedule
+	var err error
+	if schedule.minute, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return cronSchedule{}, fmt.Errorf("cron expression %q: minute: %w", expr, err)
+	}
+	if schedule.hour, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return cronSchedule{}, fmt.Errorf("cron expression %q: hour: %w", expr, err)
+	}
+	if err = schedule.parseDayOfMonth(fields[2]); err != nil {
+		return cronSchedule{}, fmt.Errorf("cron expression %q: day of month: %w", expr, err)
+	}
+	if schedule.month, err = parseCronField(fields[3], 1, 12, cronMonthNames); err != nil {
+		return cronSchedule{}, fmt.Errorf("cron expression %q: month: %w", expr, err)
+	}
+	if err = schedule.parseDayOfWeek(fields[4]); err != nil {
+		return cronSchedule{}, fmt.Errorf("cron expression %q: day of week: %w", expr, err)
+	}
+
+	return schedule, nil
+}
+
+// parseDayOfMonth parses the day of month field including the L and W
+// extensions.
+func (s *cronSchedule) parseDayOfMonth(field string) error {
+	s.dayOfMonthRestricted = field != "*" && field != "?"
+	if !s.dayOfMonthRestricted {
+		s.dayOfMonth = bitRange(1, 31)
+		return nil
+	}
+
+	var plain []string
+	for _, item := range strings.Split(field, ",") {
+		switch {
+		case strings.EqualFold(item, "L"):
+			s.lastDayOfMonth = true
+		case strings.EqualFold(item, "LW"):
+			s.lastWeekdayOfMonth = true
+		case strings.HasSuffix(strings.ToUpper(item), "W"):
+			day, err := parseCronValue(item[:len(item)-1], 1, 31, nil)
+			if err != nil {
+				return err
+			}
+			s.nearestWeekday = append(s.nearestWeekday, day)
+		default:
+			plain = append(plain, item)
+		}
+	}
+
+	if len(plain) > 0 {
+		bits, err := parseCronField(strings.Join(plain, ","), 1, 31, nil)
+		if err != nil {
+			return err
+		}
+		s.dayOfMonth = bits
+	}
+
+	return nil
+}
+
+// parseDayOfWeek parses the day of week field including the L and #
+// extensions.
+func (s *cronSchedule) parseDayOfWeek(field string) error {
+	s.dayOfWeekRestricted = field != "*" && field != "?"
+	if !s.dayOfWeekRestricted {
+		s.dayOfWeek = bitRange(0, 6)
+		return nil
+	}
+
+	var plain []string
+	for _, item := range strings.Split(field, ",") {
+		switch {
+		case strings.Contains(item, "#"):
+			weekday, n, _ := strings.Cut(item, "#")
+			day, err := parseCronValue(weekday, 0, 7, cronDayNames)
+			if err != nil {
+				return err
+			}
+			nth, err := parseCronValue(n, 1, 5, nil)
+			if err != nil {
+				return err
+			}
+			s.nthDayOfWeek = append(s.nthDayOfWeek, nthWeekday{weekday: time.Weekday(day % 7), n: nth})
+		case len(item) > 1 && strings.HasSuffix(strings.ToUpper(item), "L"):
+			day, err := parseCronValue(item[:len(item)-1], 0, 7, cronDayNames)
+			if err != nil {
+				return err
+			}
+			s.lastDayOfWeek = append(s.lastDayOfWeek, time.Weekday(day%7))
+		default:
+			plain = append(plain, item)
+		}
+	}
+
+	if len(plain) > 0 {
+		bits, err := parseCronField(strings.Join(plain, ","), 0, 7, cronDayNames)
+		if err != nil {
+			return err
+		}
+		// 7 is also Sunday
+		if bits&(1<<7) != 0 {
+			bits |= 1
+		}
+		s.dayOfWeek = bits
+	}
+
+	return nil
+}
+
+// parseCronField parses a comma separated list of values, ranges and steps
+// into a bit set.
+func parseCronField(field string, minValue, maxValue int, names map[string]int) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(item, "/")
+
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepPart)
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepPart)
+			}
+		}
+
+		var low, high int
+		switch {
+		case rangePart == "*" || rangePart == "?":
+			low, high = minValue, maxValue
+		case strings.Contains(rangePart, "-"):
+			lowPart, highPart, _ := strings.Cut(rangePart, "-")
+			var err error
+			if low, err = parseCronValue(lowPart, minValue, maxValue, names); err != nil {
+				return 0, err
+			}
+			if high, err = parseCronValue(highPart, minValue, maxValue, names); err != nil {
+				return 0, err
+			}
+			if high < low {
+				return 0, fmt.Errorf("invalid range %q", rangePart)
+			}
+		default:
+			var err error
+			if low, err = parseCronValue(rangePart, minValue, maxValue, names); err != nil {
+				return 0, err
+			}
+			high = low
+			if hasStep {
+				high = maxValue
+			}
+		}
+
+		for value := low; value <= high; value += step {
+			bits |= 1 << value
+		}
+	}
+
+	return bits, nil
+}
+
+// parseCronValue parses a single number or name within minValue and maxValue.
+func parseCronValue(value string, minValue, maxValue int, names map[string]int) (int, error) {
+	if number, ok := names[strings.ToUpper(value)]; ok {
+		return number, nil
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", value)
+	}
+	if number < minValue || number > maxValue {
+		return 0, fmt.Errorf("value %d out of range %d-%d", number, minValue, maxValue)
+	}
+
+	return number, nil
+}
+
+// bitRange a bit set with the bits from minValue to maxValue set.
+func bitRange(minValue, maxValue int) uint64 {
+	var bits uint64
+	for value := minValue; value <= maxValue; value++ {
+		bits |= 1 << value
+	}
+	return bits
+}
+
+func hasBit(bits uint64, value int) bool {
+	return bits&(1<<value) != 0
+}
+
+// matchesDay determines if the schedule has any times on the date.
+func (s cronSchedule) matchesDay(date time.Time) bool {
+	if !hasBit(s.month, int(date.Month())) {
+		return false
+	}
+
+	day := date.Day()
+	lastDay := daysIn(date.Year(), date.Month())
+
+	matchesDayOfMonth := hasBit(s.dayOfMonth, day) ||
+		s.lastDayOfMonth && day == lastDay ||
+		s.lastWeekdayOfMonth && day == nearestWeekday(date.Year(), date.Month(), lastDay)
+	for _, nearest := range s.nearestWeekday {
+		if nearest <= lastDay && day == nearestWeekday(date.Year(), date.Month(), nearest) {
+			matchesDayOfMonth = true
+		}
+	}
+
+	weekday := date.Weekday()
+	matchesDayOfWeek := hasBit(s.dayOfWeek, int(weekday))
+	for _, last := range s.lastDayOfWeek {
+		if weekday == last && day+7 > lastDay {
+			matchesDayOfWeek = true
+		}
+	}
+	for _, nth := range s.nthDayOfWeek {
+		if weekday == nth.weekday && (day-1)/7+1 == nth.n {
+			matchesDayOfWeek = true
+		}
+	}
+
+	switch {
+	case s.dayOfMonthRestricted && s.dayOfWeekRestricted:
+		return matchesDayOfMonth || matchesDayOfWeek
+	case s.dayOfMonthRestricted:
+		return matchesDayOfMonth
+	case s.dayOfWeekRestricted:
+		return matchesDayOfWeek
+	}
+
+	return true
+}
+
+// daysIn the number of days in the month.
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// nearestWeekday the day of the month for the weekday closest to the given
+// day without leaving the month.
+func nearestWeekday(year int, month time.Month, day int) int {
+	switch time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Weekday() {
+	case time.Saturday:
+		if day == 1 {
+			return day + 2
+		}
+		return day - 1
+	case time.Sunday:
+		if day == daysIn(year, month) {
+			return day - 2
+		}
+		return day + 1
+	}
+
+	return day
+}
+
+// cronOccurrences generates the occurrences of a Rule using Cron which are
+// active within the timeframe.
+func (r RuleOf[T]) cronOccurrences(viewStart, viewEnd time.Time) []EventOf[T] {
+	schedule, err := parseCron(r.Cron)
+	if err != nil {
+		// Invalid expressions are rejected by ParseCron
+		return nil
+	}
+
+	loc := r.Start.Location()
+	first := viewStart.In(loc)
+	if !r.IsOpenEnded() {
+		// Events which start before the view can still be active within it
+		first = viewStart.Add(-r.End.Sub(r.Start)).In(loc)
+	}
+	last := viewEnd.In(loc)
+
+	var occurrences []EventOf[T]
+	for date := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, loc); !date.After(last); date = date.AddDate(0, 0, 1) {
+		if !schedule.matchesDay(date) {
+			continue
+		}
+
+		for hour := 0; hour < 24; hour++ {
+			if !hasBit(schedule.hour, hour) {
+				continue
+			}
+			for minute := 0; minute < 60; minute++ {
+				if !hasBit(schedule.minute, minute) {
+					continue
+				}
+
+				start := time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, loc)
+				if start.Hour() != hour || start.Minute() != minute {
+					// The time does not exist because of daylight saving time
+					continue
+				}
+				if !r.isWithinRepeatBounds(start) {
+					continue
+				}
+
+				occurrence, ok := r.occurrenceBetween(start, r.endFor(start))
+				if !ok || !isInView(occurrence, viewStart, viewEnd) {
+					continue
+				}
+				occurrences = append(occurrences, occurrence)
+			}
+		}
+	}
+
+	return occurrences
+}
+
+// isWithinRepeatBounds determines if an occurrence starting at the given time
+// is allowed by RepeatForwardUntil and RepeatBackwardUntil.
+func (r RuleOf[T]) isWithinRepeatBounds(start time.Time) bool {
+	if start.After(r.Start) && !r.RepeatForwardUntil.IsZero() && start.After(r.RepeatForwardUntil) {
+		return false
+	}
+	if start.Before(r.Start) && !r.RepeatBackwardUntil.IsZero() && start.Before(r.RepeatBackwardUntil) {
+		return false
+	}
+
+	return true
+}
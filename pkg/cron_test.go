@@ -0,0 +1,188 @@
+package ephemeris
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		expr      string
+		viewStart time.Time
+		viewEnd   time.Time
+		expected  []time.Time
+	}{
+		{
+			desc:      "Every 15 Minutes On Weekday Mornings",
+			expr:      "*/15 9 * * MON-FRI",
+			viewStart: time.Date(2024, time.March, 8, 0, 0, 0, 0, time.UTC),
+			viewEnd:   time.Date(2024, time.March, 12, 0, 0, 0, 0, time.UTC),
+			expected: []time.Time{
+				time.Date(2024, time.March, 8, 9, 0, 0, 0, time.UTC),
+				time.Date(2024, time.March, 8, 9, 15, 0, 0, time.UTC),
+				time.Date(2024, time.March, 8, 9, 30, 0, 0, time.UTC),
+				time.Date(2024, time.March, 8, 9, 45, 0, 0, time.UTC),
+				time.Date(2024, time.March, 11, 9, 0, 0, 0, time.UTC),
+				time.Date(2024, time.March, 11, 9, 15, 0, 0, time.UTC),
+				time.Date(2024, time.March, 11, 9, 30, 0, 0, time.UTC),
+				time.Date(2024, time.March, 11, 9, 45, 0, 0, time.UTC),
+			},
+		},
+		{
+			desc:      "Daily Macro",
+			expr:      "@daily",
+			viewStart: time.Date(2024, time.March, 8, 0, 0, 0, 0, time.UTC),
+			viewEnd:   time.Date(2024, time.March, 10, 0, 0, 0, 0, time.UTC),
+			expected: []time.Time{
+				time.Date(2024, time.March, 8, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, time.March, 9, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			desc:      "Last Day Of The Month",
+			expr:      "0 12 L * *",
+			viewStart: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+			viewEnd:   time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC),
+			expected: []time.Time{
+				time.Date(2024, time.January, 31, 12, 0, 0, 0, time.UTC),
+				time.Date(2024, time.February, 29, 12, 0, 0, 0, time.UTC),
+				time.Date(2024, time.March, 31, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			desc:      "Nearest Weekday",
+			expr:      "0 9 1W,15W * *",
+			viewStart: time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC),
+			viewEnd:   time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC),
+			expected: []time.Time{
+				// The 1st is a Saturday so the closest weekday in the month is Monday the 3rd
+				time.Date(2024, time.June, 3, 9, 0, 0, 0, time.UTC),
+				// The 15th is a Saturday
+				time.Date(2024, time.June, 14, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			desc:      "Last Weekday Of The Month",
+			expr:      "0 9 LW * *",
+			viewStart: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+			viewEnd:   time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC),
+			expected: []time.Time{
+				time.Date(2024, time.March, 29, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			desc:      "Third Friday And Last Monday",
+			expr:      "30 17 * * 5#3,1L",
+			viewStart: time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC),
+			viewEnd:   time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC),
+			expected: []time.Time{
+				time.Date(2024, time.May, 17, 17, 30, 0, 0, time.UTC),
+				time.Date(2024, time.May, 27, 17, 30, 0, 0, time.UTC),
+			},
+		},
+		{
+			desc:      "Day Of Month Or Day Of Week",
+			expr:      "0 0 1 FEB SUN",
+			viewStart: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+			viewEnd:   time.Date(2024, time.December, 31, 0, 0, 0, 0, time.UTC),
+			expected: []time.Time{
+				time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, time.February, 4, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, time.February, 11, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, time.February, 18, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, time.February, 25, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			desc:      "Skips Times Missing Because Of Daylight Saving Time",
+			expr:      "30 2 * * *",
+			viewStart: time.Date(2024, time.March, 9, 0, 0, 0, 0, newYork),
+			viewEnd:   time.Date(2024, time.March, 12, 0, 0, 0, 0, newYork),
+			expected: []time.Time{
+				time.Date(2024, time.March, 9, 2, 30, 0, 0, newYork),
+				time.Date(2024, time.March, 11, 2, 30, 0, 0, newYork),
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			rule, err := ParseCron(tC.expr, 10*time.Minute, tC.viewStart.Location())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []time.Time
+			for _, e := range rule.Expand(tC.viewStart, tC.viewEnd) {
+				if e.End.Sub(e.Start) != 10*time.Minute {
+					t.Errorf("Expected a duration of 10m but got %s", e.End.Sub(e.Start))
+				}
+				got = append(got, e.Start)
+			}
+
+			if !slices.EqualFunc(tC.expected, got, time.Time.Equal) {
+				t.Errorf("Expected %v but got %v", tC.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseCronErrors(t *testing.T) {
+	testCases := []struct {
+		desc string
+		expr string
+	}{
+		{desc: "Too Few Fields", expr: "* * * *"},
+		{desc: "Too Many Fields", expr: "* * * * * *"},
+		{desc: "Unknown Macro", expr: "@fortnightly"},
+		{desc: "Minute Out Of Range", expr: "60 * * * *"},
+		{desc: "Backwards Range", expr: "* 5-1 * * *"},
+		{desc: "Invalid Step", expr: "*/0 * * * *"},
+		{desc: "Unknown Month", expr: "* * * SMARCH *"},
+		{desc: "Invalid Nth Weekday", expr: "* * * * 1#6"},
+		{desc: "Invalid Nearest Weekday", expr: "* * 32W * *"},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			if _, err := ParseCron(tC.expr, time.Hour, time.UTC); err == nil {
+				t.Errorf("Expected an error for %q", tC.expr)
+			}
+		})
+	}
+}
+
+func TestCronCalendarView(t *testing.T) {
+	backup, err := ParseCron("0 1 * * *", 2*time.Hour, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	backup.Name = "Backup"
+
+	calendar := Calendar{
+		Entries: []Rule{
+			{
+				Event: Event{
+					Start: time.Date(2024, time.March, 4, 0, 0, 0, 0, time.UTC),
+					End:   time.Date(2024, time.March, 4, 2, 0, 0, 0, time.UTC),
+					Name:  "Maintenance",
+				},
+				RepeatDaily: 1,
+			},
+			backup,
+		},
+	}
+
+	got, err := calendar.View(time.Date(2024, time.March, 4, 0, 0, 0, 0, time.UTC), time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Event{
+		{Start: time.Date(2024, time.March, 4, 0, 0, 0, 0, time.UTC), End: time.Date(2024, time.March, 4, 1, 0, 0, 0, time.UTC), Name: "Maintenance", RecurrenceID: time.Date(2024, time.March, 4, 0, 0, 0, 0, time.UTC), Truncated: true},
+		{Start: time.Date(2024, time.March, 4, 1, 0, 0, 0, time.UTC), End: time.Date(2024, time.March, 4, 3, 0, 0, 0, time.UTC), Name: "Backup", RecurrenceID: time.Date(2024, time.March, 4, 1, 0, 0, 0, time.UTC)},
+	}
+	if !slices.Equal(expected, got) {
+		t.Errorf("Expected %v but got %v", expected, got)
+	}
+}
@@ -0,0 +1,249 @@
+package ephemeris
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ErrUnknownField is returned when a calendar definition file has a field
+// which is not part of a Calendar.
+var ErrUnknownField = errors.New("unknown field")
+
+// Calendar definition files are YAML or TOML documents with the same fields as
+// the JSON representation of a Calendar, see JSONSchema. Files are checked
+// against JSONSchema before they are read so errors can point to the line of
+// the field which is wrong.
+
+// ReadYAML reads a Calendar from a YAML calendar definition file.
+func ReadYAML[T any](r io.Reader) (CalendarOf[T], error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return CalendarOf[T]{}, err
+	}
+
+	document, err := parseYAML(data)
+	if err != nil {
+		return CalendarOf[T]{}, err
+	}
+	return readDefinition[T](document)
+}
+
+// ReadTOML reads a Calendar from a TOML calendar definition file.
+func ReadTOML[T any](r io.Reader) (CalendarOf[T], error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return CalendarOf[T]{}, err
+	}
+
+	document, err := parseTOML(data)
+	if err != nil {
+		return CalendarOf[T]{}, err
+	}
+	return readDefinition[T](document)
+}
+
+// WriteYAML writes the canonical YAML calendar definition file of the
+// Calendar, which has its fields in the same order as MarshalJSON.
+func WriteYAML[T any](w io.Writer, c CalendarOf[T]) error {
+	document, err := newDefinition(c)
+	if err != nil {
+		return err
+	}
+	return writeYAML(w, document)
+}
+
+// WriteTOML writes the canonical TOML calendar definition file of the
+// Calendar, which has its fields in the same order as MarshalJSON except that
+// tables come after the other fields of their parent table.
+func WriteTOML[T any](w io.Writer, c CalendarOf[T]) error {
+	document, err := newDefinition(c)
+	if err != nil {
+		return err
+	}
+	return writeTOML(w, document)
+}
+
+func readDefinition[T any](document *node) (CalendarOf[T], error) {
+	if err := calendarSchema.check(document, ""); err != nil {
+		return CalendarOf[T]{}, err
+	}
+
+	var c CalendarOf[T]
+	if err := json.Unmarshal(document.appendJSON(nil), &c); err != nil {
+		return CalendarOf[T]{}, err
+	}
+	return c, nil
+}
+
+// newDefinition converts the Calendar to a document using its JSON
+// representation.
+func newDefinition[T any](c CalendarOf[T]) (*node, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return parseJSON(decoder)
+}
+
+type nodeKind int
+
+const (
+	nodeNull nodeKind = iota
+	nodeBool
+	nodeNumber
+	nodeString
+	nodeArray
+	nodeObject
+)
+
+var nodeKindNames = map[nodeKind]string{
+	nodeNull:   "null",
+	nodeBool:   "boolean",
+	nodeNumber: "number",
+	nodeString: "string",
+	nodeArray:  "array",
+	nodeObject: "object",
+}
+
+// node a value of a calendar definition file along with the line it is on.
+type node struct {
+	kind nodeKind
+	line int
+
+	// value the text of a bool, number, or string.
+	value  string
+	items  []*node
+	fields []field
+}
+
+// field a field of an object node, in the order of the file.
+type field struct {
+	key   string
+	line  int
+	value *node
+}
+
+func (n *node) get(key string) *node {
+	for _, f := range n.fields {
+		if f.key == key {
+			return f.value
+		}
+	}
+	return nil
+}
+
+// isInteger whether the node is a number without a fraction or exponent.
+func (n *node) isInteger() bool {
+	return n.kind == nodeNumber && !strings.ContainsAny(n.value, ".eE")
+}
+
+func (n *node) appendJSON(b []byte) []byte {
+	switch n.kind {
+	case nodeBool, nodeNumber:
+		return append(b, n.value...)
+	case nodeString:
+		return appendJSONString(b, n.value)
+	case nodeArray:
+		b = append(b, '[')
+		for i, item := range n.items {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = item.appendJSON(b)
+		}
+		return append(b, ']')
+	case nodeObject:
+		b = append(b, '{')
+		for i, f := range n.fields {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = appendJSONString(b, f.key)
+			b = append(b, ':')
+			b = f.value.appendJSON(b)
+		}
+		return append(b, '}')
+	default:
+		return append(b, "null"...)
+	}
+}
+
+// appendJSONString appends the quoted JSON string without escaping HTML.
+func appendJSONString(b []byte, s string) []byte {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	// Encoding a string can not fail
+	_ = encoder.Encode(s)
+	return append(b, bytes.TrimSuffix(buf.Bytes(), []byte("\n"))...)
+}
+
+// parseJSON reads the next value from a decoder which uses numbers.
+func parseJSON(decoder *json.Decoder) (*node, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token := token.(type) {
+	case json.Delim:
+		if token == '[' {
+			n := &node{kind: nodeArray}
+			for decoder.More() {
+				item, err := parseJSON(decoder)
+				if err != nil {
+					return nil, err
+				}
+				n.items = append(n.items, item)
+			}
+			_, err := decoder.Token()
+			return n, err
+		}
+
+		n := &node{kind: nodeObject}
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := parseJSON(decoder)
+			if err != nil {
+				return nil, err
+			}
+			n.fields = append(n.fields, field{key: key.(string), value: value})
+		}
+		_, err := decoder.Token()
+		return n, err
+	case bool:
+		return &node{kind: nodeBool, value: strconv.FormatBool(token)}, nil
+	case json.Number:
+		return &node{kind: nodeNumber, value: token.String()}, nil
+	case string:
+		return &node{kind: nodeString, value: token}, nil
+	default:
+		return &node{kind: nodeNull}, nil
+	}
+}
+
+// lineError an error with the line and path of the field it is for.
+func lineError(line int, path string, err error) error {
+	if path == "" {
+		return fmt.Errorf("line %d: %w", line, err)
+	}
+	return fmt.Errorf("line %d: %s: %w", line, path, err)
+}
+
+func fieldPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,423 @@
+package ephemeris
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// definitionCalendar the Calendar of the definition files used by the tests.
+func definitionCalendar() Calendar {
+	at := func(day, hour int) time.Time {
+		return time.Date(2024, time.March, day, hour, 0, 0, 0, newYork)
+	}
+
+	return Calendar{
+		Name: "Operations",
+		Entries: []Rule{
+			{
+				ID:                 "standup",
+				Event:              Event{Start: at(4, 9), End: at(4, 9).Add(15 * time.Minute), Name: "Standup", Metadata: &Metadata{Tags: []string{"team"}}},
+				RepeatWeekly:       1,
+				RepeatForwardUntil: at(29, 9),
+				Skip:               []time.Time{at(11, 9)},
+				Canceled:           []time.Time{at(18, 9)},
+				Overrides:          []Event{{RecurrenceID: at(25, 9), Start: at(25, 10), Name: "Late Standup"}},
+			},
+			{
+				Event:  Event{Name: "Notes"},
+				Anchor: &Anchor{RuleID: "standup", Offset: 30 * time.Minute, FromEnd: true, Duration: 90 * time.Second},
+			},
+		},
+		MinimumDuration: time.Minute,
+		Tracks:          []Track{{Name: "team", Capacity: 2}},
+	}
+}
+
+const definitionYAML = `schemaVersion: 1
+name: Operations
+entries:
+  - start: "2024-03-04T09:00:00-05:00"
+    end: "2024-03-04T09:15:00-05:00"
+    timeZone: America/New_York
+    name: Standup
+    metadata:
+      tags:
+        - team
+    id: standup
+    repeatWeekly: 1
+    repeatForwardUntil: "2024-03-29T09:00:00-04:00"
+    skip:
+      - "2024-03-11T09:00:00-04:00"
+    canceled:
+      - "2024-03-18T09:00:00-04:00"
+    overrides:
+      - start: "2024-03-25T10:00:00-04:00"
+        timeZone: America/New_York
+        name: Late Standup
+        recurrenceId: "2024-03-25T09:00:00-04:00"
+  - name: Notes
+    anchor:
+      ruleId: standup
+      offset: PT30M
+      fromEnd: true
+      duration: PT1M30S
+minimumDuration: PT1M
+tracks:
+  - name: team
+    capacity: 2
+`
+
+const definitionTOML = `schemaVersion = 1
+name = "Operations"
+minimumDuration = "PT1M"
+
+[[entries]]
+start = "2024-03-04T09:00:00-05:00"
+end = "2024-03-04T09:15:00-05:00"
+timeZone = "America/New_York"
+name = "Standup"
+id = "standup"
+repeatWeekly = 1
+repeatForwardUntil = "2024-03-29T09:00:00-04:00"
+skip = ["2024-03-11T09:00:00-04:00"]
+canceled = ["2024-03-18T09:00:00-04:00"]
+
+[entries.metadata]
+tags = ["team"]
+
+[[entries.overrides]]
+start = "2024-03-25T10:00:00-04:00"
+timeZone = "America/New_York"
+name = "Late Standup"
+recurrenceId = "2024-03-25T09:00:00-04:00"
+
+[[entries]]
+name = "Notes"
+
+[entries.anchor]
+ruleId = "standup"
+offset = "PT30M"
+fromEnd = true
+duration = "PT1M30S"
+
+[[tracks]]
+name = "team"
+capacity = 2
+`
+
+func TestWriteDefinition(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		write    func(*bytes.Buffer, Calendar) error
+		expected string
+	}{
+		{desc: "YAML", write: func(b *bytes.Buffer, c Calendar) error { return WriteYAML(b, c) }, expected: definitionYAML},
+		{desc: "TOML", write: func(b *bytes.Buffer, c Calendar) error { return WriteTOML(b, c) }, expected: definitionTOML},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			var got bytes.Buffer
+			if err := tC.write(&got, definitionCalendar()); err != nil {
+				t.Fatal(err)
+			}
+			if got.String() != tC.expected {
+				t.Errorf("Expected\n%s\nbut got\n%s", tC.expected, got.String())
+			}
+		})
+	}
+}
+
+func TestReadDefinition(t *testing.T) {
+	testCases := []struct {
+		desc string
+		read func(string) (Calendar, error)
+		file string
+	}{
+		{desc: "Canonical YAML", read: readYAML, file: definitionYAML},
+		{desc: "Canonical TOML", read: readTOML, file: definitionTOML},
+		{
+			desc: "Handwritten YAML",
+			read: readYAML,
+			file: `# Operations schedule
+name: Operations
+minimumDuration: 1m
+tracks: [{name: team, capacity: 2}]
+entries:
+  - id: standup
+    name: Standup
+    start: 2024-03-04T09:00:00-05:00
+    end: 2024-03-04T09:15:00-05:00
+    timeZone: America/New_York
+    repeatWeekly: 1
+    repeatForwardUntil: 2024-03-29T13:00:00Z
+    skip: [2024-03-11T09:00:00-04:00]
+    canceled: [2024-03-18T09:00:00-04:00]
+    metadata: {tags: [team]}
+    overrides:
+      - recurrenceId: 2024-03-25T09:00:00-04:00
+        start: 2024-03-25T10:00:00-04:00
+        timeZone: America/New_York
+        name: Late Standup
+  - name: Notes
+    anchor: {ruleId: standup, offset: 30m, fromEnd: true, duration: 1m30s}
+`,
+		},
+		{
+			desc: "Handwritten TOML",
+			read: readTOML,
+			file: `# Operations schedule
+name = "Operations"
+minimumDuration = "1m"
+tracks = [{ name = "team", capacity = 2 }]
+
+[[entries]]
+id = "standup"
+name = "Standup"
+start = 2024-03-04T09:00:00-05:00
+end = 2024-03-04 09:15:00-05:00
+timeZone = "America/New_York"
+repeatWeekly = 1
+repeatForwardUntil = 2024-03-29T13:00:00Z
+skip = [2024-03-11T09:00:00-04:00]
+canceled = [2024-03-18T09:00:00-04:00]
+metadata.tags = ["team"]
+overrides = [
+  { recurrenceId = 2024-03-25T09:00:00-04:00, start = 2024-03-25T10:00:00-04:00, timeZone = "America/New_York", name = "Late Standup" },
+]
+
+[[entries]]
+name = "Notes"
+anchor = { ruleId = "standup", offset = "30m", fromEnd = true, duration = "1m30s" }
+`,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			got, err := tC.read(tC.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Calendars are compared as JSON since the times which are read
+			// have a different, but equivalent, location
+			expectedJSON, _ := json.Marshal(definitionCalendar())
+			gotJSON, _ := json.Marshal(got)
+			if string(expectedJSON) != string(gotJSON) {
+				t.Errorf("Expected\n%s\nbut got\n%s", expectedJSON, gotJSON)
+			}
+		})
+	}
+}
+
+func TestDefinitionRoundTrip(t *testing.T) {
+	type setpoint struct {
+		Celsius float64  `json:"celsius"`
+		Zones   []string `json:"zones,omitempty"`
+	}
+
+	hours := WeekdayHours(9*time.Hour, 17*time.Hour, newYork)
+	holidays := Calendar{Entries: []Rule{{Event: Event{Start: time.Date(2024, time.July, 4, 0, 0, 0, 0, newYork), Name: "Independence Day", AllDay: true}, RepeatDateAnually: 1}}}
+	calendar := CalendarOf[setpoint]{
+		Name: "Heating",
+		Entries: []RuleOf[setpoint]{
+			{
+				Event:               Event{Start: time.Date(2024, time.March, 4, 7, 0, 0, 0, newYork), Name: "Comfort"},
+				Payload:             setpoint{Celsius: 20.5, Zones: []string{"living room", "kitchen"}},
+				RepeatBusinessDaily: 1,
+				Holidays:            &holidays,
+				ByHour:              []int{7, 17},
+			},
+			{
+				Event: Event{Start: time.Date(2024, time.March, 4, 22, 0, 0, 0, time.UTC), Name: "Off \"eco\" & quiet", Status: StatusCanceled},
+				Cron:  "0 22 * * *",
+			},
+		},
+		Merge:        MergeByPayload,
+		Idle:         &EventOf[setpoint]{Name: "Idle"},
+		WorkingHours: &hours,
+	}
+
+	testCases := []struct {
+		desc  string
+		write func(*bytes.Buffer, CalendarOf[setpoint]) error
+		read  func(*bytes.Buffer) (CalendarOf[setpoint], error)
+	}{
+		{
+			desc:  "YAML",
+			write: func(b *bytes.Buffer, c CalendarOf[setpoint]) error { return WriteYAML(b, c) },
+			read:  func(b *bytes.Buffer) (CalendarOf[setpoint], error) { return ReadYAML[setpoint](b) },
+		},
+		{
+			desc:  "TOML",
+			write: func(b *bytes.Buffer, c CalendarOf[setpoint]) error { return WriteTOML(b, c) },
+			read:  func(b *bytes.Buffer) (CalendarOf[setpoint], error) { return ReadTOML[setpoint](b) },
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			var file bytes.Buffer
+			if err := tC.write(&file, calendar); err != nil {
+				t.Fatal(err)
+			}
+			written := file.String()
+
+			got, err := tC.read(&file)
+			if err != nil {
+				t.Fatalf("%v\n%s", err, written)
+			}
+
+			expectedJSON, _ := json.Marshal(calendar)
+			gotJSON, _ := json.Marshal(got)
+			if string(expectedJSON) != string(gotJSON) {
+				t.Errorf("Expected\n%s\nbut got\n%s\nfrom\n%s", expectedJSON, gotJSON, written)
+			}
+		})
+	}
+}
+
+func TestReadDefinitionErrors(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		read     func(string) (Calendar, error)
+		file     string
+		expected string
+	}{
+		{
+			desc:     "YAML Unknown Field",
+			read:     readYAML,
+			file:     "name: Operations\nentries:\n  - name: Standup\n    repeatDialy: 1\n",
+			expected: "line 4: entries[0].repeatDialy: unknown field",
+		},
+		{
+			desc:     "TOML Unknown Field",
+			read:     readTOML,
+			file:     "name = \"Operations\"\n\n[[entries]]\nname = \"Standup\"\nrepeatDialy = 1\n",
+			expected: "line 5: entries[0].repeatDialy: unknown field",
+		},
+		{
+			desc:     "Unknown Field Of An Override",
+			read:     readYAML,
+			file:     "entries:\n  - name: Standup\n    overrides:\n      - name: Late\n        repeatDaily: 1\n",
+			expected: "line 5: entries[0].overrides[0].repeatDaily: unknown field",
+		},
+		{
+			desc:     "Unknown Field Of A Nested Table",
+			read:     readTOML,
+			file:     "[[entries]]\nname = \"Standup\"\n\n[entries.anchor]\nruleId = \"a\"\nstart = \"PT1H\"\n",
+			expected: "line 6: entries[0].anchor.start: unknown field",
+		},
+		{
+			desc:     "Unknown Top Level Field",
+			read:     readTOML,
+			file:     "title = \"Operations\"\n",
+			expected: "line 1: title: unknown field",
+		},
+		{
+			desc:     "Wrong Type",
+			read:     readYAML,
+			file:     "entries:\n  - name: Standup\n    repeatDaily: daily\n",
+			expected: `line 3: entries[0].repeatDaily: expected an integer but got "daily"`,
+		},
+		{
+			desc:     "Out Of Range",
+			read:     readTOML,
+			file:     "[[entries]]\nbyHour = [9, 24]\n",
+			expected: "line 2: entries[0].byHour[1]: expected at most 23 but got 24",
+		},
+		{
+			desc:     "Invalid Time",
+			read:     readYAML,
+			file:     "entries:\n  - name: Standup\n    start: 2024-03-04 09:00\n",
+			expected: `line 3: entries[0].start: invalid time "2024-03-04 09:00", expected an RFC 3339 timestamp`,
+		},
+		{
+			desc:     "Local Time",
+			read:     readTOML,
+			file:     "[[entries]]\nstart = 2024-03-04T09:00:00\n",
+			expected: `line 2: entries[0].start: invalid time "2024-03-04T09:00:00", expected an RFC 3339 timestamp`,
+		},
+		{
+			desc:     "Invalid Duration",
+			read:     readYAML,
+			file:     "name: Operations\nminimumDuration: P1M\n",
+			expected: `line 2: minimumDuration: invalid duration "P1M"`,
+		},
+		{
+			desc:     "Invalid Enum",
+			read:     readTOML,
+			file:     "merge = \"color\"\n",
+			expected: `line 1: merge: expected one of "name", "ruleId", "payload" but got "color"`,
+		},
+		{
+			desc:     "Invalid Day",
+			read:     readYAML,
+			file:     "workingHours:\n  days:\n    funday: [{start: 9h, end: 17h}]\n",
+			expected: `line 3: workingHours.days.funday: expected one of "sunday"`,
+		},
+		{
+			desc:     "Missing Required Field",
+			read:     readYAML,
+			file:     "entries:\n  - name: Notes\n    anchor:\n      offset: 30m\n",
+			expected: "line 4: entries[0].anchor: missing ruleId",
+		},
+		{
+			desc:     "Newer Schema Version",
+			read:     readYAML,
+			file:     "schemaVersion: 2\n",
+			expected: "line 1: schemaVersion: expected 1 but got 2",
+		},
+		{
+			desc:     "YAML Duplicate Key",
+			read:     readYAML,
+			file:     "name: Operations\nname: Ops\n",
+			expected: "line 2: name: defined more than once",
+		},
+		{
+			desc:     "TOML Duplicate Key",
+			read:     readTOML,
+			file:     "name = \"Operations\"\nname = \"Ops\"\n",
+			expected: "line 2: name: defined more than once",
+		},
+		{
+			desc:     "TOML Duplicate Table",
+			read:     readTOML,
+			file:     "[[entries]]\nname = \"Standup\"\n\n[entries.metadata]\ntags = [\"team\"]\n\n[entries.metadata]\ncolor = \"#ff0000\"\n",
+			expected: "line 7: metadata: defined more than once",
+		},
+		{
+			desc:     "YAML Syntax",
+			read:     readYAML,
+			file:     "name: Operations\nentries: [\n",
+			expected: "line 2",
+		},
+		{
+			desc:     "TOML Syntax",
+			read:     readTOML,
+			file:     "name = \"Operations\"\nentries = [\n",
+			expected: "line 2",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			_, err := tC.read(tC.file)
+			if err == nil || !strings.Contains(err.Error(), tC.expected) {
+				t.Fatalf("Expected an error containing %q but got %v", tC.expected, err)
+			}
+			if strings.Contains(tC.expected, "unknown field") && !errors.Is(err, ErrUnknownField) {
+				t.Errorf("Expected %v to be %v", err, ErrUnknownField)
+			}
+		})
+	}
+}
+
+func readYAML(file string) (Calendar, error) {
+	return ReadYAML[struct{}](strings.NewReader(file))
+}
+
+func readTOML(file string) (Calendar, error) {
+	return ReadTOML[struct{}](strings.NewReader(file))
+}
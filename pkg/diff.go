@@ -0,0 +1,232 @@
+package ephemeris
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+)
+
+// ChangeKind describes how something differs between two Calendars.
+type ChangeKind int
+
+const (
+	// ChangeAdded only the second Calendar has it.
+	ChangeAdded ChangeKind = iota + 1
+	// ChangeRemoved only the first Calendar has it.
+	ChangeRemoved
+	// ChangeChanged both Calendars have it but it is different.
+	ChangeChanged
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeAdded:
+		return "added"
+	case ChangeRemoved:
+		return "removed"
+	case ChangeChanged:
+		return "changed"
+	}
+	return fmt.Sprintf("ChangeKind(%d)", int(k))
+}
+
+// SegmentChangeOf a period of time where the Views of two Calendars differ.
+type SegmentChangeOf[T any] struct {
+	Kind ChangeKind
+	Interval
+	// Before the Event of the first Calendar during the period, the zero
+	// value when the segment was added.
+	Before EventOf[T]
+	// After the Event of the second Calendar during the period, the zero value
+	// when the segment was removed.
+	After EventOf[T]
+}
+
+// SegmentChange a SegmentChangeOf Events without a Payload.
+type SegmentChange = SegmentChangeOf[struct{}]
+
+// DiffViews compares the Views of two Calendars, such as two versions of the
+// same Calendar, for the timeframe of viewStart(inclusive) and
+// viewEnd(exclusive) and returns the periods where they differ, ordered by
+// their Start. Instants which differ are returned as segments which end when
+// they start.
+//
+// Events are the same when all their fields, other than Start, End, Truncated
+// and Split, are equal, so a period where an Event was only shortened by
+// condensing differently is not a change. Touching periods with the same
+// change are combined.
+func DiffViews[T any](a, b CalendarOf[T], viewStart, viewEnd time.Time) ([]SegmentChangeOf[T], error) {
+	before, err := a.View(viewStart, viewEnd)
+	if err != nil {
+		return nil, err
+	}
+	after, err := b.View(viewStart, viewEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	points := []time.Time{viewStart, viewEnd}
+	for _, event := range slices.Concat(before, after) {
+		if !event.IsInstant() {
+			points = append(points, latest(event.Start, viewStart), earliest(event.effectiveEnd(), viewEnd))
+		}
+	}
+	slices.SortFunc(points, func(a, b time.Time) int { return a.Compare(b) })
+	points = slices.CompactFunc(points, time.Time.Equal)
+
+	var changes []SegmentChangeOf[T]
+	for i := 0; i+1 < len(points); i++ {
+		start, end := points[i], points[i+1]
+		beforeEvent, inBefore := activeAt(before, start)
+		afterEvent, inAfter := activeAt(after, start)
+
+		change := SegmentChangeOf[T]{Interval: Interval{Start: start, End: end}, Before: beforeEvent, After: afterEvent}
+		switch {
+		case inBefore && inAfter && !sameEvent(beforeEvent, afterEvent):
+			change.Kind = ChangeChanged
+		case inBefore && !inAfter:
+			change.Kind = ChangeRemoved
+		case !inBefore && inAfter:
+			change.Kind = ChangeAdded
+		default:
+			continue
+		}
+
+		if last := len(changes) - 1; last >= 0 && changes[last].End.Equal(start) && changes[last].Kind == change.Kind &&
+			sameEvent(changes[last].Before, change.Before) && sameEvent(changes[last].After, change.After) {
+			changes[last].End = end
+			continue
+		}
+		changes = append(changes, change)
+	}
+
+	changes = append(changes, instantChanges(before, after, ChangeRemoved)...)
+	changes = append(changes, instantChanges(after, before, ChangeAdded)...)
+	slices.SortStableFunc(changes, func(a, b SegmentChangeOf[T]) int {
+		return a.Start.Compare(b.Start)
+	})
+
+	return changes, nil
+}
+
+// activeAt finds the Event, which is not an instant, which contains t.
+func activeAt[T any](events []EventOf[T], t time.Time) (EventOf[T], bool) {
+	for _, event := range events {
+		if !event.IsInstant() && event.contains(t) {
+			return event, true
+		}
+	}
+	return EventOf[T]{}, false
+}
+
+// instantChanges creates changes of the kind for the instants of events which
+// are not in others.
+func instantChanges[T any](events, others []EventOf[T], kind ChangeKind) []SegmentChangeOf[T] {
+	var changes []SegmentChangeOf[T]
+	for _, event := range events {
+		if !event.IsInstant() {
+			continue
+		}
+		if slices.ContainsFunc(others, func(other EventOf[T]) bool {
+			return other.IsInstant() && other.Start.Equal(event.Start) && sameEvent(event, other)
+		}) {
+			continue
+		}
+
+		change := SegmentChangeOf[T]{Kind: kind, Interval: Interval{Start: event.Start, End: event.End}}
+		if kind == ChangeRemoved {
+			change.Before = event
+		} else {
+			change.After = event
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// sameEvent determines if the Events are the same other than their Start, End,
+// and how they were condensed.
+func sameEvent[T any](e1, e2 EventOf[T]) bool {
+	for _, e := range []*EventOf[T]{&e1, &e2} {
+		e.Start, e.End = time.Time{}, time.Time{}
+		e.Truncated, e.Split = false, false
+	}
+	return reflect.DeepEqual(e1, e2)
+}
+
+// RuleChangeOf a Rule which differs between two Calendars.
+type RuleChangeOf[T any] struct {
+	Kind ChangeKind
+	// ID the ID of the Rule, or its position within Entries such as "#2"
+	// when it does not have one.
+	ID     string
+	Before RuleOf[T]
+	After  RuleOf[T]
+	// Fields the names of the fields which are different when the Rule was
+	// changed, with the fields of the Event being repeated named directly,
+	// such as "Start" or "RepeatDaily".
+	Fields []string
+}
+
+// RuleChange a RuleChangeOf Events without a Payload.
+type RuleChange = RuleChangeOf[struct{}]
+
+// DiffRules compares the Rules of two Calendars. Rules are matched by their ID
+// and Rules without an ID are matched by their position within Entries. The
+// changes are ordered by the position of the Rules within the first Calendar
+// followed by the Rules which were added.
+func DiffRules[T any](a, b CalendarOf[T]) []RuleChangeOf[T] {
+	key := func(i int, rule RuleOf[T]) string {
+		if rule.ID != "" {
+			return rule.ID
+		}
+		return fmt.Sprintf("#%d", i)
+	}
+
+	afterRules := map[string]RuleOf[T]{}
+	for i, rule := range b.Entries {
+		afterRules[key(i, rule)] = rule
+	}
+
+	var changes []RuleChangeOf[T]
+	matched := map[string]bool{}
+	for i, before := range a.Entries {
+		id := key(i, before)
+		after, ok := afterRules[id]
+		if !ok {
+			changes = append(changes, RuleChangeOf[T]{Kind: ChangeRemoved, ID: id, Before: before})
+			continue
+		}
+
+		matched[id] = true
+		if fields := changedFields(reflect.ValueOf(before), reflect.ValueOf(after)); len(fields) > 0 {
+			changes = append(changes, RuleChangeOf[T]{Kind: ChangeChanged, ID: id, Before: before, After: after, Fields: fields})
+		}
+	}
+
+	for i, after := range b.Entries {
+		if id := key(i, after); !matched[id] {
+			changes = append(changes, RuleChangeOf[T]{Kind: ChangeAdded, ID: id, After: after})
+		}
+	}
+
+	return changes
+}
+
+// changedFields the names of the fields of the structs which are different,
+// including the fields of embedded structs.
+func changedFields(before, after reflect.Value) []string {
+	var fields []string
+	for i := range before.NumField() {
+		field := before.Type().Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			fields = append(fields, changedFields(before.Field(i), after.Field(i))...)
+			continue
+		}
+		if !reflect.DeepEqual(before.Field(i).Interface(), after.Field(i).Interface()) {
+			fields = append(fields, field.Name)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,84 @@
+package ephemeris
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, time.March, 4, hour, minute, 0, 0, time.UTC)
+	}
+
+	work := Rule{ID: "work", Event: Event{Start: at(9, 0), End: at(17, 0), Name: "Work"}}
+	lunch := Rule{ID: "lunch", Event: Event{Start: at(12, 0), End: at(13, 0), Name: "Lunch"}}
+	deploy := Rule{ID: "deploy", Event: Event{Start: at(15, 0), End: at(15, 0), Name: "Deploy"}}
+	before := Calendar{Entries: []Rule{work, lunch, deploy}}
+
+	work.End = at(18, 0)
+	lunch.Start, lunch.End = at(12, 30), at(13, 30)
+	gym := Rule{ID: "gym", Event: Event{Start: at(19, 0), End: at(20, 0), Name: "Gym"}}
+	after := Calendar{Entries: []Rule{work, lunch, gym}}
+
+	t.Run("Views", func(t *testing.T) {
+		got, err := DiffViews(before, after, at(0, 0), at(24, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		workBefore := Event{Start: at(13, 0), End: at(17, 0), Name: "Work", RecurrenceID: at(9, 0), RuleID: "work", Truncated: true, Split: true}
+		lunchBefore := Event{Start: at(12, 0), End: at(13, 0), Name: "Lunch", RecurrenceID: at(12, 0), RuleID: "lunch"}
+		workAfter := Event{Start: at(9, 0), End: at(12, 30), Name: "Work", RecurrenceID: at(9, 0), RuleID: "work", Truncated: true, Split: true}
+		lunchAfter := Event{Start: at(12, 30), End: at(13, 30), Name: "Lunch", RecurrenceID: at(12, 30), RuleID: "lunch"}
+		expected := []SegmentChange{
+			{Kind: ChangeChanged, Interval: Interval{Start: at(12, 0), End: at(12, 30)}, Before: lunchBefore, After: workAfter},
+			{Kind: ChangeChanged, Interval: Interval{Start: at(12, 30), End: at(13, 0)}, Before: lunchBefore, After: lunchAfter},
+			{Kind: ChangeChanged, Interval: Interval{Start: at(13, 0), End: at(13, 30)}, Before: workBefore, After: lunchAfter},
+			{Kind: ChangeRemoved, Interval: Interval{Start: at(15, 0), End: at(15, 0)}, Before: Event{Start: at(15, 0), End: at(15, 0), Name: "Deploy", RecurrenceID: at(15, 0), RuleID: "deploy"}},
+			{Kind: ChangeAdded, Interval: Interval{Start: at(17, 0), End: at(18, 0)}, After: Event{Start: at(13, 30), End: at(18, 0), Name: "Work", RecurrenceID: at(9, 0), RuleID: "work", Truncated: true, Split: true}},
+			{Kind: ChangeAdded, Interval: Interval{Start: at(19, 0), End: at(20, 0)}, After: Event{Start: at(19, 0), End: at(20, 0), Name: "Gym", RecurrenceID: at(19, 0), RuleID: "gym"}},
+		}
+		if len(got) != len(expected) {
+			t.Fatalf("Expected %v but got %v", expected, got)
+		}
+		for i := range expected {
+			if got[i] != expected[i] {
+				t.Errorf("Expected %v but got %v", expected[i], got[i])
+			}
+		}
+	})
+
+	t.Run("Views Without Changes", func(t *testing.T) {
+		got, err := DiffViews(before, before, at(0, 0), at(24, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 0 {
+			t.Errorf("Expected no changes but got %v", got)
+		}
+	})
+
+	t.Run("Rules", func(t *testing.T) {
+		got := DiffRules(before, after)
+
+		expected := []struct {
+			kind   ChangeKind
+			id     string
+			fields []string
+		}{
+			{kind: ChangeChanged, id: "work", fields: []string{"End"}},
+			{kind: ChangeChanged, id: "lunch", fields: []string{"Start", "End"}},
+			{kind: ChangeRemoved, id: "deploy"},
+			{kind: ChangeAdded, id: "gym"},
+		}
+		if len(got) != len(expected) {
+			t.Fatalf("Expected %v but got %v", expected, got)
+		}
+		for i, e := range expected {
+			if got[i].Kind != e.kind || got[i].ID != e.id || !slices.Equal(e.fields, got[i].Fields) {
+				t.Errorf("Expected %s %s %v but got %s %s %v", e.kind, e.id, e.fields, got[i].Kind, got[i].ID, got[i].Fields)
+			}
+		}
+	})
+}
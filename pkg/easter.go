@@ -0,0 +1,82 @@
+package ephemeris
+
+import "time"
+
+// Computus the method used to calculate the date of Easter.
+type Computus int
+
+const (
+	// ComputusNone Easter is not used.
+	ComputusNone Computus = iota
+	// ComputusWestern Easter as observed by Western churches, calculated with
+	// the Gregorian calendar.
+	ComputusWestern
+	// ComputusOrthodox Easter as observed by Eastern Orthodox churches,
+	// calculated with the Julian calendar.
+	ComputusOrthodox
+)
+
+// Date the date of Easter Sunday in the year using the Computus, as midnight
+// UTC in the Gregorian calendar. The zero time is returned for ComputusNone.
+func (c Computus) Date(year int) time.Time {
+	switch c {
+	case ComputusWestern:
+		return Easter(year)
+	case ComputusOrthodox:
+		return OrthodoxEaster(year)
+	}
+
+	return time.Time{}
+}
+
+// Easter the date of Western Easter Sunday in the year, as midnight UTC, using
+// the anonymous Gregorian algorithm.
+func Easter(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+// OrthodoxEaster the date of Orthodox Easter Sunday in the year, as midnight
+// UTC in the Gregorian calendar, using Meeus' Julian algorithm.
+func OrthodoxEaster(year int) time.Time {
+	a := year % 4
+	b := year % 7
+	c := year % 19
+	d := (19*c + 15) % 30
+	e := (2*a + 4*b - d + 34) % 7
+	month := (d + e + 114) / 31
+	day := (d+e+114)%31 + 1
+
+	// Easter is always after February so the difference between the Julian
+	// and Gregorian calendars for the year can be used
+	julianOffset := year/100 - year/400 - 2
+
+	return time.Date(year, time.Month(month), day+julianOffset, 0, 0, 0, 0, time.UTC)
+}
+
+// repeatEaster calculates the Start and End times of the nth occurrence of a
+// Rule using RepeatEaster, which is the nth year after the Start.
+func (r RuleOf[T]) repeatEaster(n int) (time.Time, time.Time) {
+	year := r.Start.Year() + n
+	date := r.RepeatEaster.Date(year).AddDate(0, 0, r.EasterOffsetDays)
+
+	// Move the Start and End by whole days so their times of day are kept
+	startDate := time.Date(r.Start.Year(), r.Start.Month(), r.Start.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(date.Sub(startDate).Hours() / 24)
+
+	return r.Start.AddDate(0, 0, days), r.End.AddDate(0, 0, days)
+}
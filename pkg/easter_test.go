@@ -0,0 +1,95 @@
+package ephemeris
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestComputusDate(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		computus Computus
+		year     int
+		expected time.Time
+	}{
+		{desc: "Western 1818", computus: ComputusWestern, year: 1818, expected: time.Date(1818, time.March, 22, 0, 0, 0, 0, time.UTC)},
+		{desc: "Western 2024", computus: ComputusWestern, year: 2024, expected: time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC)},
+		{desc: "Western 2025", computus: ComputusWestern, year: 2025, expected: time.Date(2025, time.April, 20, 0, 0, 0, 0, time.UTC)},
+		{desc: "Western 2038", computus: ComputusWestern, year: 2038, expected: time.Date(2038, time.April, 25, 0, 0, 0, 0, time.UTC)},
+		{desc: "Orthodox 2021", computus: ComputusOrthodox, year: 2021, expected: time.Date(2021, time.May, 2, 0, 0, 0, 0, time.UTC)},
+		{desc: "Orthodox 2023", computus: ComputusOrthodox, year: 2023, expected: time.Date(2023, time.April, 16, 0, 0, 0, 0, time.UTC)},
+		{desc: "Orthodox 2024", computus: ComputusOrthodox, year: 2024, expected: time.Date(2024, time.May, 5, 0, 0, 0, 0, time.UTC)},
+		{desc: "Orthodox 2025", computus: ComputusOrthodox, year: 2025, expected: time.Date(2025, time.April, 20, 0, 0, 0, 0, time.UTC)},
+		{desc: "None", computus: ComputusNone, year: 2025, expected: time.Time{}},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			got := tC.computus.Date(tC.year)
+			if !got.Equal(tC.expected) {
+				t.Errorf("Expected %s but got %s", tC.expected, got)
+			}
+		})
+	}
+}
+
+func TestExpandEaster(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rule     Rule
+		expected []time.Time
+	}{
+		{
+			desc: "Good Friday",
+			rule: Rule{
+				Event: Event{
+					Start: time.Date(2024, time.March, 29, 10, 0, 0, 0, newYork),
+					End:   time.Date(2024, time.March, 29, 12, 0, 0, 0, newYork),
+					Name:  "Good Friday Service",
+				},
+				RepeatEaster:     ComputusWestern,
+				EasterOffsetDays: -2,
+			},
+			expected: []time.Time{
+				time.Date(2023, time.April, 7, 10, 0, 0, 0, newYork),
+				time.Date(2024, time.March, 29, 10, 0, 0, 0, newYork),
+				time.Date(2025, time.April, 18, 10, 0, 0, 0, newYork),
+			},
+		},
+		{
+			desc: "Orthodox Easter Monday",
+			rule: Rule{
+				Event: Event{
+					Start: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+					End:   time.Date(2024, time.January, 2, 0, 0, 0, 0, time.UTC),
+					Name:  "Easter Monday",
+				},
+				RepeatEaster:     ComputusOrthodox,
+				EasterOffsetDays: 1,
+			},
+			expected: []time.Time{
+				time.Date(2023, time.April, 17, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, time.May, 6, 0, 0, 0, 0, time.UTC),
+				time.Date(2025, time.April, 21, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			viewStart := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+			viewEnd := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+			var got []time.Time
+			for _, e := range tC.rule.Expand(viewStart, viewEnd) {
+				if e.End.Sub(e.Start) != tC.rule.End.Sub(tC.rule.Start) {
+					t.Errorf("Expected a duration of %s but got %s", tC.rule.End.Sub(tC.rule.Start), e.End.Sub(e.Start))
+				}
+				got = append(got, e.Start)
+			}
+
+			if !slices.EqualFunc(tC.expected, got, time.Time.Equal) {
+				t.Errorf("Expected %v but got %v", tC.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package ephemeris
+
+import (
+	"slices"
+	"time"
+)
+
+// removeShortEvents removes the Events, which are ordered by their Start, that
+// condensing truncated to less than minimumDuration. When absorb is set the
+// Event which ends at the Start of a removed Event is extended to its End, or
+// when there is none the Event which starts at its End is moved to its Start.
+func removeShortEvents[T any](events []EventOf[T], minimumDuration time.Duration, absorb bool) []EventOf[T] {
+	var remainingEvents []EventOf[T]
+	for i := 0; i < len(events); i++ {
+		event := events[i]
+		if !isShort(event, minimumDuration) {
+			remainingEvents = append(remainingEvents, event)
+			continue
+		}
+		if !absorb {
+			continue
+		}
+
+		if previous := lastBounded(remainingEvents); previous >= 0 && remainingEvents[previous].End.Equal(event.Start) {
+			remainingEvents[previous].End = event.End
+			continue
+		}
+		if next := firstBounded(events[i+1:]); next >= 0 && events[i+1+next].Start.Equal(event.End) {
+			events[i+1+next].Start = event.Start
+		}
+	}
+
+	return remainingEvents
+}
+
+// isShort determines if the Event was truncated to less than minimumDuration.
+func isShort[T any](e EventOf[T], minimumDuration time.Duration) bool {
+	return e.Truncated && !e.IsInstant() && !e.IsOpenEnded() && e.End.Sub(e.Start) < minimumDuration
+}
+
+// lastBounded finds the index of the last Event which is not an instant.
+func lastBounded[T any](events []EventOf[T]) int {
+	for i := len(events) - 1; i >= 0; i-- {
+		if !events[i].IsInstant() {
+			return i
+		}
+	}
+	return -1
+}
+
+// firstBounded finds the index of the first Event which is not an instant.
+func firstBounded[T any](events []EventOf[T]) int {
+	for i, event := range events {
+		if !event.IsInstant() {
+			return i
+		}
+	}
+	return -1
+}
+
+// fillGaps adds a copy of the idle Event for each part of the timeframe of
+// viewStart(inclusive) and viewEnd(exclusive) which is not covered by any of the
+// Events, which are ordered by their Start and do not overlap.
+func fillGaps[T any](events []EventOf[T], idle EventOf[T], viewStart, viewEnd time.Time) []EventOf[T] {
+	var filledEvents []EventOf[T]
+	addIdle := func(start, end time.Time) {
+		if !start.Before(end) {
+			return
+		}
+		gap := idle
+		gap.Start, gap.End = start, end
+		filledEvents = append(filledEvents, gap)
+	}
+
+	covered := viewStart
+	for _, event := range events {
+		if !event.IsInstant() {
+			addIdle(covered, event.Start)
+			if event.effectiveEnd().After(covered) {
+				covered = event.effectiveEnd()
+			}
+		}
+		filledEvents = append(filledEvents, event)
+	}
+	addIdle(covered, viewEnd)
+
+	// Instants within a gap are before the idle Event which fills it
+	slices.SortStableFunc(filledEvents, func(a, b EventOf[T]) int {
+		return a.Start.Compare(b.Start)
+	})
+
+	return filledEvents
+}
@@ -0,0 +1,110 @@
+package ephemeris
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestMinimumDuration(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, time.March, 4, hour, minute, 0, 0, time.UTC)
+	}
+
+	work := Rule{ID: "work", Event: Event{Start: at(9, 0), End: at(17, 0), Name: "Work"}}
+	entries := []Rule{
+		work,
+		{ID: "early", Event: Event{Start: at(9, 2), End: at(10, 0), Name: "Early"}},
+		{ID: "first", Event: Event{Start: at(12, 0), End: at(12, 58), Name: "First"}},
+		{ID: "second", Event: Event{Start: at(13, 0), End: at(14, 0), Name: "Second"}},
+	}
+
+	testCases := []struct {
+		desc     string
+		absorb   bool
+		expected []Event
+	}{
+		{
+			desc:   "Removed",
+			absorb: false,
+			expected: []Event{
+				{Start: at(9, 2), End: at(10, 0), Name: "Early", RecurrenceID: at(9, 2), RuleID: "early"},
+				{Start: at(10, 0), End: at(12, 0), Name: "Work", RecurrenceID: at(9, 0), RuleID: "work", Truncated: true, Split: true},
+				{Start: at(12, 0), End: at(12, 58), Name: "First", RecurrenceID: at(12, 0), RuleID: "first"},
+				{Start: at(13, 0), End: at(14, 0), Name: "Second", RecurrenceID: at(13, 0), RuleID: "second"},
+				{Start: at(14, 0), End: at(17, 0), Name: "Work", RecurrenceID: at(9, 0), RuleID: "work", Truncated: true, Split: true},
+			},
+		},
+		{
+			desc:   "Absorbed",
+			absorb: true,
+			expected: []Event{
+				{Start: at(9, 0), End: at(10, 0), Name: "Early", RecurrenceID: at(9, 2), RuleID: "early"},
+				{Start: at(10, 0), End: at(12, 0), Name: "Work", RecurrenceID: at(9, 0), RuleID: "work", Truncated: true, Split: true},
+				{Start: at(12, 0), End: at(13, 0), Name: "First", RecurrenceID: at(12, 0), RuleID: "first"},
+				{Start: at(13, 0), End: at(14, 0), Name: "Second", RecurrenceID: at(13, 0), RuleID: "second"},
+				{Start: at(14, 0), End: at(17, 0), Name: "Work", RecurrenceID: at(9, 0), RuleID: "work", Truncated: true, Split: true},
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			calendar := Calendar{Entries: entries, MinimumDuration: 5 * time.Minute, AbsorbShortEvents: tC.absorb}
+
+			got, err := calendar.View(at(0, 0), at(24, 0))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !slices.Equal(tC.expected, got) {
+				t.Errorf("Expected %v but got %v", tC.expected, got)
+			}
+		})
+	}
+
+	t.Run("Short Events Which Were Not Truncated Are Kept", func(t *testing.T) {
+		calendar := Calendar{
+			Entries:         []Rule{{Event: Event{Start: at(9, 0), End: at(9, 1), Name: "Check In"}}},
+			MinimumDuration: 5 * time.Minute,
+		}
+
+		got, err := calendar.View(at(0, 0), at(24, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 1 {
+			t.Errorf("Expected the short Event to be kept but got %v", got)
+		}
+	})
+}
+
+func TestIdleEvent(t *testing.T) {
+	at := func(hour int) time.Time {
+		return time.Date(2024, time.March, 4, hour, 0, 0, 0, time.UTC)
+	}
+
+	calendar := Calendar{
+		Entries: []Rule{
+			{Event: Event{Start: at(7), End: at(9), Name: "Commute"}},
+			{Event: Event{Start: at(12), End: at(12), Name: "Alarm"}},
+			{Event: Event{Start: at(14), End: at(16), Name: "Meeting"}},
+		},
+		Idle: &Event{Name: "Free"},
+	}
+
+	got, err := calendar.View(at(8), at(18))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Event{
+		{Start: at(7), End: at(9), Name: "Commute", RecurrenceID: at(7)},
+		{Start: at(9), End: at(14), Name: "Free"},
+		{Start: at(12), End: at(12), Name: "Alarm", RecurrenceID: at(12)},
+		{Start: at(14), End: at(16), Name: "Meeting", RecurrenceID: at(14)},
+		{Start: at(16), End: at(18), Name: "Free"},
+	}
+	if !slices.Equal(expected, got) {
+		t.Errorf("Expected %v but got %v", expected, got)
+	}
+}
@@ -0,0 +1,124 @@
+package ephemeris
+
+import (
+	"slices"
+	"time"
+)
+
+// WorkingHours the times of each day of the week which are available, for
+// example 9:00 to 17:00 from Monday to Friday.
+type WorkingHours struct {
+	// Location the times of day are in, nil uses the location of the start
+	// of the timeframe they are used for.
+	Location *time.Location
+
+	// Days the available times for each day of the week, indexed by
+	// time.Weekday. A day without any DayWindows is not available.
+	Days [7][]DayWindow
+}
+
+// DayWindow a time of day from Start(inclusive) to End(exclusive), each
+// measured from midnight by the clock, so 9 hours is 9:00 even on days where
+// daylight saving time begins or ends. An End past 24 hours continues into the
+// next day.
+type DayWindow struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// WeekdayHours WorkingHours from start to end on Monday to Friday.
+func WeekdayHours(start, end time.Duration, loc *time.Location) WorkingHours {
+	hours := WorkingHours{Location: loc}
+	for day := time.Monday; day <= time.Friday; day++ {
+		hours.Days[day] = []DayWindow{{Start: start, End: end}}
+	}
+	return hours
+}
+
+// intervals the available times within the timeframe of viewStart(inclusive)
+// and viewEnd(exclusive), ordered by their Start.
+func (w WorkingHours) intervals(viewStart, viewEnd time.Time) []Interval {
+	loc := w.Location
+	if loc == nil {
+		loc = viewStart.Location()
+	}
+
+	var intervals []Interval
+	// Windows from the day before the view may continue into it
+	for day := startOfDay(viewStart.In(loc)).AddDate(0, 0, -1); day.Before(viewEnd); day = day.AddDate(0, 0, 1) {
+		for _, window := range w.Days[day.Weekday()] {
+			intervals = append(intervals, Interval{
+				Start: time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, int(window.Start), loc),
+				End:   time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, int(window.End), loc),
+			})
+		}
+	}
+
+	return intersectIntervals(unionIntervals(intervals), []Interval{{Start: viewStart, End: viewEnd}})
+}
+
+// FreeSlots returns the parts of the timeframe of viewStart(inclusive) and
+// viewEnd(exclusive) which are not covered by any Event of the View, and are
+// within the WorkingHours of the Calendar when it has them. Only free time
+// lasting at least minDuration is returned, ordered by Start.
+//
+// Instants do not take up any time and the Idle Event of the Calendar is not
+// used, so neither prevent time from being free.
+func (c *CalendarOf[T]) FreeSlots(viewStart, viewEnd time.Time, minDuration time.Duration) ([]Interval, error) {
+	free, err := c.free(viewStart, viewEnd, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.DeleteFunc(free, func(slot Interval) bool {
+		return slot.Duration() < minDuration
+	}), nil
+}
+
+// free does the work of FreeSlots, without the minimum duration, where the time
+// from bufferBefore the Start of each Event until bufferAfter its End is not
+// free.
+func (c *CalendarOf[T]) free(viewStart, viewEnd time.Time, bufferBefore, bufferAfter time.Duration) ([]Interval, error) {
+	busy, err := c.busy(viewStart.Add(-bufferAfter), viewEnd.Add(bufferBefore), bufferBefore, bufferAfter)
+	if err != nil {
+		return nil, err
+	}
+
+	free := subtractIntervals([]Interval{{Start: viewStart, End: viewEnd}}, busy)
+	if c.WorkingHours != nil {
+		free = intersectIntervals(free, c.WorkingHours.intervals(viewStart, viewEnd))
+	}
+
+	return free, nil
+}
+
+// busy the times within the timeframe covered by the Events of the View,
+// without the Idle Event, widened by the buffers and ordered by their Start.
+func (c *CalendarOf[T]) busy(viewStart, viewEnd time.Time, bufferBefore, bufferAfter time.Duration) ([]Interval, error) {
+	// Short fragments of Events are still busy, even when the View would
+	// remove them or give their time to another Event
+	calendar := *c
+	calendar.Idle = nil
+	calendar.MinimumDuration = 0
+	calendar.AbsorbShortEvents = false
+
+	events, err := calendar.View(viewStart, viewEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	var busy []Interval
+	for _, event := range events {
+		if event.IsInstant() {
+			continue
+		}
+
+		interval := Interval{Start: event.Start.Add(-bufferBefore), End: event.effectiveEnd()}
+		if !event.IsOpenEnded() {
+			interval.End = interval.End.Add(bufferAfter)
+		}
+		busy = append(busy, interval)
+	}
+
+	return unionIntervals(busy), nil
+}
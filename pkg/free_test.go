@@ -0,0 +1,139 @@
+package ephemeris
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestFreeSlots(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, time.March, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	entries := []Rule{
+		{Event: Event{Start: at(4, 10, 0), End: at(4, 11, 0), Name: "Standup"}, RepeatDaily: 1, RepeatBackwardUntil: at(4, 10, 0)},
+		{Event: Event{Start: at(4, 11, 15), End: at(4, 12, 0), Name: "Review"}},
+		{Event: Event{Start: at(4, 13, 0), End: at(4, 13, 0), Name: "Alarm"}},
+		{Event: Event{Start: at(5, 10, 0), End: at(5, 10, 30), Name: "Canceled"}, Canceled: []time.Time{at(5, 10, 0)}},
+	}
+	nineToFive := WeekdayHours(9*time.Hour, 17*time.Hour, time.UTC)
+
+	testCases := []struct {
+		desc         string
+		workingHours *WorkingHours
+		viewStart    time.Time
+		viewEnd      time.Time
+		minDuration  time.Duration
+		expected     []Interval
+	}{
+		{
+			desc:        "Any Time",
+			viewStart:   at(4, 0, 0),
+			viewEnd:     at(5, 0, 0),
+			minDuration: 0,
+			expected: []Interval{
+				{Start: at(4, 0, 0), End: at(4, 10, 0)},
+				{Start: at(4, 11, 0), End: at(4, 11, 15)},
+				{Start: at(4, 12, 0), End: at(5, 0, 0)},
+			},
+		},
+		{
+			desc:        "Minimum Duration",
+			viewStart:   at(4, 0, 0),
+			viewEnd:     at(5, 0, 0),
+			minDuration: 30 * time.Minute,
+			expected: []Interval{
+				{Start: at(4, 0, 0), End: at(4, 10, 0)},
+				{Start: at(4, 12, 0), End: at(5, 0, 0)},
+			},
+		},
+		{
+			desc:         "Working Hours",
+			workingHours: &nineToFive,
+			viewStart:    at(4, 0, 0),
+			viewEnd:      at(5, 0, 0),
+			minDuration:  30 * time.Minute,
+			expected: []Interval{
+				{Start: at(4, 9, 0), End: at(4, 10, 0)},
+				{Start: at(4, 12, 0), End: at(4, 17, 0)},
+			},
+		},
+		{
+			desc:         "Working Hours Over A Weekend",
+			workingHours: &nineToFive,
+			viewStart:    at(8, 12, 0),
+			viewEnd:      at(11, 12, 0),
+			minDuration:  time.Hour,
+			expected: []Interval{
+				{Start: at(8, 12, 0), End: at(8, 17, 0)},
+				{Start: at(11, 9, 0), End: at(11, 10, 0)},
+				{Start: at(11, 11, 0), End: at(11, 12, 0)},
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			calendar := Calendar{Entries: entries, WorkingHours: tC.workingHours}
+
+			got, err := calendar.FreeSlots(tC.viewStart, tC.viewEnd, tC.minDuration)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !slices.Equal(tC.expected, got) {
+				t.Errorf("Expected %v but got %v", tC.expected, got)
+			}
+		})
+	}
+}
+
+func TestFreeSlotsShortEvents(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, time.March, 4, hour, minute, 0, 0, time.UTC)
+	}
+
+	calendar := Calendar{
+		Entries: []Rule{
+			{Event: Event{Start: at(9, 0), End: at(12, 0), Name: "Workshop"}},
+			{Event: Event{Start: at(11, 0), End: at(11, 50), Name: "Call"}},
+		},
+		// The Workshop is left with 10 minutes after the Call
+		MinimumDuration: 15 * time.Minute,
+	}
+
+	got, err := calendar.FreeSlots(at(8, 0), at(13, 0), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Interval{
+		{Start: at(8, 0), End: at(9, 0)},
+		{Start: at(12, 0), End: at(13, 0)},
+	}
+	if !slices.Equal(expected, got) {
+		t.Errorf("Expected %v but got %v", expected, got)
+	}
+}
+
+func TestWorkingHoursIntervals(t *testing.T) {
+	hours := WorkingHours{Location: newYork}
+	hours.Days[time.Sunday] = []DayWindow{{Start: 9 * time.Hour, End: 17 * time.Hour}}
+	hours.Days[time.Monday] = []DayWindow{{Start: 22 * time.Hour, End: 30 * time.Hour}}
+
+	// Daylight saving time begins on March 10th 2024 in New York
+	got := hours.intervals(time.Date(2024, time.March, 10, 0, 0, 0, 0, newYork), time.Date(2024, time.March, 12, 0, 0, 0, 0, newYork))
+
+	expected := []Interval{
+		{Start: time.Date(2024, time.March, 10, 9, 0, 0, 0, newYork), End: time.Date(2024, time.March, 10, 17, 0, 0, 0, newYork)},
+		{Start: time.Date(2024, time.March, 11, 22, 0, 0, 0, newYork), End: time.Date(2024, time.March, 12, 0, 0, 0, 0, newYork)},
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %v but got %v", expected, got)
+	}
+	for i := range expected {
+		if !got[i].Start.Equal(expected[i].Start) || !got[i].End.Equal(expected[i].End) {
+			t.Errorf("Expected %v but got %v", expected[i], got[i])
+		}
+	}
+}
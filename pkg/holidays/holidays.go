@@ -0,0 +1,287 @@
+// Package holidays provides Calendars of public holidays for several
+// jurisdictions. The holidays are computed from the rules which define them,
+// such as a fixed date, the nth day of the week in a month or a number of
+// days from Easter, so they do not need to be maintained by hand each year.
+//
+// The Calendars are meant to be used as the holidays for business days or to
+// be added to the end of the Entries of another Calendar so the holidays take
+// precedence over everything else.
+//
+// Jurisdiction.Calendar creates one repeating Rule for each holiday on a fixed
+// date or a number of days from Easter which is never moved to the date it is
+// observed on. Other holidays get a Rule which does not repeat for every year
+// in the range, as the date they are observed on depends on the day of the
+// week they fall on and on the other holidays of the year, which the repeat
+// options of a Rule can not express. Views outside of the range of years have
+// no holidays, so the range should cover every View the Calendar is used for.
+package holidays
+
+import (
+	"slices"
+	"time"
+
+	ephemeris "github.com/AnthonyMBonafide/ephemeris/pkg"
+)
+
+// Jurisdiction the holidays observed in a country or region.
+type Jurisdiction struct {
+	Name string
+
+	holidays []holiday
+	// observe moves holidays which fall on a weekend to the day they are
+	// observed on.
+	observe observance
+}
+
+// Calendar creates a Calendar with the holidays from fromYear to toYear,
+// inclusive. Each holiday is an all day Event on the date it is observed,
+// which is expanded to midnight in the location of the view. Holidays which
+// are observed on a different date than they fall on are named with an
+// "(observed)" suffix, which may move them into the previous or next year, for
+// example New Year's Day on a Saturday being observed on the Friday before.
+//
+// The repeating Rules come first, in the order the holidays are defined,
+// followed by the Rules for the other holidays ordered by date.
+func (j Jurisdiction) Calendar(fromYear, toYear int) ephemeris.Calendar {
+	calendar := ephemeris.Calendar{Name: j.Name}
+	for _, h := range j.holidays {
+		if rule, ok := h.rule(j.observance(h), fromYear, toYear); ok {
+			calendar.Entries = append(calendar.Entries, rule)
+		}
+	}
+
+	for year := fromYear; year <= toYear; year++ {
+		for _, observed := range j.observedIn(year) {
+			if observed.repeats {
+				continue
+			}
+			calendar.Entries = append(calendar.Entries, ephemeris.Rule{
+				Event: ephemeris.Event{
+					Start:  observed.date,
+					End:    observed.date.AddDate(0, 0, 1),
+					Name:   observed.name,
+					AllDay: true,
+				},
+			})
+		}
+	}
+
+	return calendar
+}
+
+// observance the observance of the holiday, which replaces the one of the
+// Jurisdiction when it is set.
+func (j Jurisdiction) observance(h holiday) observance {
+	if h.observe != nil {
+		return h.observe
+	}
+	return j.observe
+}
+
+// observedHoliday a holiday on the date it is observed in a year.
+type observedHoliday struct {
+	name    string
+	date    time.Time
+	observe observance
+	// repeats the holiday is in the Calendar as a repeating Rule.
+	repeats bool
+}
+
+// observedIn computes the dates the holidays are observed on in the year,
+// ordered by date.
+func (j Jurisdiction) observedIn(year int) []observedHoliday {
+	var observed []observedHoliday
+	taken := map[time.Time]bool{}
+	for _, h := range j.holidays {
+		if h.since > year {
+			continue
+		}
+
+		observe := j.observance(h)
+		o := observedHoliday{name: h.name, date: h.date(year), observe: observe, repeats: h.repeats(observe)}
+		observed = append(observed, o)
+
+		if !isWeekend(o.date) {
+			taken[o.date] = true
+		}
+	}
+	slices.SortStableFunc(observed, func(a, b observedHoliday) int {
+		return a.date.Compare(b.date)
+	})
+
+	// Holidays falling on a weekday are kept first so substitute days for
+	// weekend holidays move around them.
+	for i, o := range observed {
+		if o.observe == nil {
+			continue
+		}
+
+		date := o.observe(o.date, taken)
+		if !date.Equal(o.date) {
+			observed[i].name = o.name + " (observed)"
+			observed[i].date = date
+			taken[date] = true
+		}
+	}
+
+	slices.SortStableFunc(observed, func(a, b observedHoliday) int {
+		return a.date.Compare(b.date)
+	})
+	return observed
+}
+
+// holiday defines how to compute the date of a holiday in any year.
+type holiday struct {
+	name string
+	date func(year int) time.Time
+	// since the first year the holiday is observed.
+	since int
+	// observe replaces the observance of the Jurisdiction for this holiday.
+	observe observance
+
+	// repeat sets the repeat options of a Rule for the holiday, which is nil
+	// when the repeat options can not express its date.
+	repeat func(rule *ephemeris.Rule)
+	// onWeekday the holiday always falls on Monday to Friday.
+	onWeekday bool
+}
+
+// repeats determines if the holiday is always observed on the date it falls
+// on, so it can be a repeating Rule. Observances only move holidays which fall
+// on a weekend.
+func (h holiday) repeats(observe observance) bool {
+	if h.repeat == nil {
+		return false
+	}
+	return observe == nil || h.onWeekday
+}
+
+// rule a Rule repeating the holiday every year from fromYear, or the year
+// it is first observed in, to toYear. False is returned when the holiday
+// can not be a repeating Rule or is not observed within the years.
+func (h holiday) rule(observe observance, fromYear, toYear int) (ephemeris.Rule, bool) {
+	first := max(fromYear, h.since)
+	if !h.repeats(observe) || first > toYear {
+		return ephemeris.Rule{}, false
+	}
+
+	start := h.date(first)
+	rule := ephemeris.Rule{
+		Event: ephemeris.Event{
+			Start:  start,
+			End:    start.AddDate(0, 0, 1),
+			Name:   h.name,
+			AllDay: true,
+		},
+		RepeatBackwardUntil: start,
+		RepeatForwardUntil:  h.date(toYear),
+	}
+	h.repeat(&rule)
+
+	return rule, true
+}
+
+// startingIn sets the first year the holiday is observed.
+func (h holiday) startingIn(year int) holiday {
+	h.since = year
+	return h
+}
+
+// observed sets the observance of the holiday.
+func (h holiday) observed(observe observance) holiday {
+	h.observe = observe
+	return h
+}
+
+// fixed a holiday on the same date every year.
+func fixed(name string, month time.Month, day int) holiday {
+	return holiday{
+		name: name,
+		date: func(year int) time.Time {
+			return date(year, month, day)
+		},
+		repeat: func(rule *ephemeris.Rule) {
+			rule.RepeatDateAnually = 1
+		},
+	}
+}
+
+// nthWeekday a holiday on the nth weekday of a month, for example the 4th
+// Thursday of November. Negative values of n count back from the end of the
+// month so -1 is the last weekday of the month.
+func nthWeekday(name string, month time.Month, weekday time.Weekday, n int) holiday {
+	return holiday{name: name, date: func(year int) time.Time {
+		if n < 0 {
+			last := date(year, month+1, 0)
+			offset := (int(last.Weekday()) - int(weekday) + 7) % 7
+			return last.AddDate(0, 0, -offset+7*(n+1))
+		}
+
+		first := date(year, month, 1)
+		offset := (int(weekday) - int(first.Weekday()) + 7) % 7
+		return first.AddDate(0, 0, offset+7*(n-1))
+	}}
+}
+
+// easterRelative a holiday a number of days from Easter Sunday in the
+// Gregorian calendar, for example -2 for Good Friday.
+func easterRelative(name string, days int) holiday {
+	// Easter is always on a Sunday
+	weekday := time.Weekday((days%7 + 7) % 7)
+	return holiday{
+		name: name,
+		date: func(year int) time.Time {
+			return ephemeris.Easter(year).AddDate(0, 0, days)
+		},
+		repeat: func(rule *ephemeris.Rule) {
+			rule.RepeatEaster = ephemeris.ComputusWestern
+			rule.EasterOffsetDays = days
+		},
+		onWeekday: weekday != time.Saturday && weekday != time.Sunday,
+	}
+}
+
+// observance moves a holiday which falls on a weekend to the date it is
+// observed on. Dates which are already taken by other holidays are provided
+// so substitute days do not fall on them.
+type observance func(date time.Time, taken map[time.Time]bool) time.Time
+
+// nearestWeekday observes holidays on a Saturday on the Friday before and on a
+// Sunday on the Monday after.
+func nearestWeekday(d time.Time, _ map[time.Time]bool) time.Time {
+	switch d.Weekday() {
+	case time.Saturday:
+		return d.AddDate(0, 0, -1)
+	case time.Sunday:
+		return d.AddDate(0, 0, 1)
+	}
+	return d
+}
+
+// nextFreeWeekday observes holidays on a weekend on the next weekday which is
+// not already a holiday.
+func nextFreeWeekday(d time.Time, taken map[time.Time]bool) time.Time {
+	if !isWeekend(d) {
+		return d
+	}
+	for isWeekend(d) || taken[d] {
+		d = d.AddDate(0, 0, 1)
+	}
+	return d
+}
+
+// saturdayBefore observes holidays on a Sunday on the Saturday before.
+func saturdayBefore(d time.Time, _ map[time.Time]bool) time.Time {
+	if d.Weekday() == time.Sunday {
+		return d.AddDate(0, 0, -1)
+	}
+	return d
+}
+
+func isWeekend(d time.Time) bool {
+	return d.Weekday() == time.Saturday || d.Weekday() == time.Sunday
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,225 @@
+package holidays
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	ephemeris "github.com/AnthonyMBonafide/ephemeris/pkg"
+)
+
+func TestJurisdictionCalendar(t *testing.T) {
+	type holidayDate struct {
+		name string
+		date time.Time
+	}
+
+	testCases := []struct {
+		desc         string
+		jurisdiction Jurisdiction
+		year         int
+		expected     []holidayDate
+	}{
+		{
+			desc:         "United States Observed Dates",
+			jurisdiction: UnitedStates,
+			year:         2021,
+			expected: []holidayDate{
+				{"New Year's Day", date(2021, time.January, 1)},
+				{"Martin Luther King Jr. Day", date(2021, time.January, 18)},
+				{"Washington's Birthday", date(2021, time.February, 15)},
+				{"Memorial Day", date(2021, time.May, 31)},
+				{"Juneteenth National Independence Day (observed)", date(2021, time.June, 18)},
+				{"Independence Day (observed)", date(2021, time.July, 5)},
+				{"Labor Day", date(2021, time.September, 6)},
+				{"Columbus Day", date(2021, time.October, 11)},
+				{"Veterans Day", date(2021, time.November, 11)},
+				{"Thanksgiving Day", date(2021, time.November, 25)},
+				{"Christmas Day (observed)", date(2021, time.December, 24)},
+			},
+		},
+		{
+			desc:         "United States Holiday Observed In The Previous Year",
+			jurisdiction: UnitedStates,
+			year:         2022,
+			expected: []holidayDate{
+				{"New Year's Day (observed)", date(2021, time.December, 31)},
+				{"Martin Luther King Jr. Day", date(2022, time.January, 17)},
+				{"Washington's Birthday", date(2022, time.February, 21)},
+				{"Memorial Day", date(2022, time.May, 30)},
+				{"Juneteenth National Independence Day (observed)", date(2022, time.June, 20)},
+				{"Independence Day", date(2022, time.July, 4)},
+				{"Labor Day", date(2022, time.September, 5)},
+				{"Columbus Day", date(2022, time.October, 10)},
+				{"Veterans Day", date(2022, time.November, 11)},
+				{"Thanksgiving Day", date(2022, time.November, 24)},
+				{"Christmas Day (observed)", date(2022, time.December, 26)},
+			},
+		},
+		{
+			desc:         "United Kingdom Substitute Days",
+			jurisdiction: UnitedKingdom,
+			year:         2021,
+			expected: []holidayDate{
+				{"New Year's Day", date(2021, time.January, 1)},
+				{"Good Friday", date(2021, time.April, 2)},
+				{"Easter Monday", date(2021, time.April, 5)},
+				{"Early May Bank Holiday", date(2021, time.May, 3)},
+				{"Spring Bank Holiday", date(2021, time.May, 31)},
+				{"Summer Bank Holiday", date(2021, time.August, 30)},
+				{"Christmas Day (observed)", date(2021, time.December, 27)},
+				{"Boxing Day (observed)", date(2021, time.December, 28)},
+			},
+		},
+		{
+			desc:         "United Kingdom Christmas On A Sunday",
+			jurisdiction: UnitedKingdom,
+			year:         2022,
+			expected: []holidayDate{
+				{"New Year's Day (observed)", date(2022, time.January, 3)},
+				{"Good Friday", date(2022, time.April, 15)},
+				{"Easter Monday", date(2022, time.April, 18)},
+				{"Early May Bank Holiday", date(2022, time.May, 2)},
+				{"Spring Bank Holiday", date(2022, time.May, 30)},
+				{"Summer Bank Holiday", date(2022, time.August, 29)},
+				{"Boxing Day", date(2022, time.December, 26)},
+				{"Christmas Day (observed)", date(2022, time.December, 27)},
+			},
+		},
+		{
+			desc:         "Germany Easter Based Holidays",
+			jurisdiction: Germany,
+			year:         2024,
+			expected: []holidayDate{
+				{"New Year's Day", date(2024, time.January, 1)},
+				{"Good Friday", date(2024, time.March, 29)},
+				{"Easter Monday", date(2024, time.April, 1)},
+				{"Labour Day", date(2024, time.May, 1)},
+				{"Ascension Day", date(2024, time.May, 9)},
+				{"Whit Monday", date(2024, time.May, 20)},
+				{"German Unity Day", date(2024, time.October, 3)},
+				{"Christmas Day", date(2024, time.December, 25)},
+				{"Second Day of Christmas", date(2024, time.December, 26)},
+			},
+		},
+		{
+			desc:         "Netherlands King's Day On A Sunday",
+			jurisdiction: Netherlands,
+			year:         2025,
+			expected: []holidayDate{
+				{"New Year's Day", date(2025, time.January, 1)},
+				{"Good Friday", date(2025, time.April, 18)},
+				{"Easter Sunday", date(2025, time.April, 20)},
+				{"Easter Monday", date(2025, time.April, 21)},
+				{"King's Day (observed)", date(2025, time.April, 26)},
+				{"Ascension Day", date(2025, time.May, 29)},
+				{"Whit Sunday", date(2025, time.June, 8)},
+				{"Whit Monday", date(2025, time.June, 9)},
+				{"Christmas Day", date(2025, time.December, 25)},
+				{"Second Day of Christmas", date(2025, time.December, 26)},
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			calendar := tC.jurisdiction.Calendar(tC.year, tC.year)
+			// Observed dates may move into the years around the range
+			events, err := calendar.Expand(date(tC.year-1, time.January, 1), date(tC.year+2, time.January, 1))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []holidayDate
+			for _, e := range events {
+				if !e.AllDay || e.End.Sub(e.Start) != 24*time.Hour {
+					t.Errorf("Expected %s to last a whole day but got %s", e.Name, e.End.Sub(e.Start))
+				}
+				got = append(got, holidayDate{e.Name, e.Start})
+			}
+
+			if !slices.Equal(tC.expected, got) {
+				t.Errorf("Expected %v but got %v", tC.expected, got)
+			}
+		})
+	}
+}
+
+func TestJurisdictionCalendarRepeatingRules(t *testing.T) {
+	// Holidays in Germany are never moved so each one is a single Rule
+	calendar := Germany.Calendar(1980, 2100)
+	if len(calendar.Entries) != len(Germany.holidays) {
+		t.Errorf("Expected %d rules but got %d", len(Germany.holidays), len(calendar.Entries))
+	}
+
+	testCases := []struct {
+		desc     string
+		year     int
+		expected int
+	}{
+		{
+			desc:     "Before German Unity Day",
+			year:     1985,
+			expected: 8,
+		},
+		{
+			desc:     "Last Year Of The Range",
+			year:     2100,
+			expected: 9,
+		},
+		{
+			desc:     "After The Range",
+			year:     2101,
+			expected: 0,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			events, err := calendar.Expand(date(tC.year, time.January, 1), date(tC.year+1, time.January, 1))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(events) != tC.expected {
+				t.Errorf("Expected %d holidays but got %v", tC.expected, events)
+			}
+		})
+	}
+}
+
+func TestHolidaysAsLayer(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	holidays := UnitedStates.Calendar(2024, 2024)
+	work := ephemeris.Calendar{
+		Name: "Work",
+		Entries: []ephemeris.Rule{
+			{
+				Event: ephemeris.Event{
+					Start: time.Date(2024, time.July, 1, 9, 0, 0, 0, newYork),
+					End:   time.Date(2024, time.July, 1, 17, 0, 0, 0, newYork),
+					Name:  "Working",
+				},
+				RepeatBusinessDaily: 1,
+				Holidays:            &holidays,
+			},
+		},
+	}
+	// Holidays are added last so they take precedence
+	work.Entries = append(work.Entries, holidays.Entries...)
+
+	view, err := work.View(time.Date(2024, time.July, 3, 0, 0, 0, 0, newYork), time.Date(2024, time.July, 6, 0, 0, 0, 0, newYork))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, e := range view {
+		names = append(names, e.Name)
+	}
+	if expected := []string{"Working", "Independence Day", "Working"}; !slices.Equal(expected, names) {
+		t.Errorf("Expected %v but got %v", expected, names)
+	}
+}
@@ -0,0 +1,96 @@
+package holidays
+
+import "time"
+
+// UnitedStates the federal holidays of the United States. Holidays on a
+// Saturday are observed on the Friday before and holidays on a Sunday are
+// observed on the Monday after.
+var UnitedStates = Jurisdiction{
+	Name: "United States",
+	holidays: []holiday{
+		fixed("New Year's Day", time.January, 1),
+		nthWeekday("Martin Luther King Jr. Day", time.January, time.Monday, 3).startingIn(1986),
+		nthWeekday("Washington's Birthday", time.February, time.Monday, 3),
+		nthWeekday("Memorial Day", time.May, time.Monday, -1),
+		fixed("Juneteenth National Independence Day", time.June, 19).startingIn(2021),
+		fixed("Independence Day", time.July, 4),
+		nthWeekday("Labor Day", time.September, time.Monday, 1),
+		nthWeekday("Columbus Day", time.October, time.Monday, 2),
+		fixed("Veterans Day", time.November, 11),
+		nthWeekday("Thanksgiving Day", time.November, time.Thursday, 4),
+		fixed("Christmas Day", time.December, 25),
+	},
+	observe: nearestWeekday,
+}
+
+// UnitedKingdom the bank holidays of England and Wales. Holidays on a weekend
+// are observed on the next weekday which is not already a holiday. One off
+// bank holidays, such as for coronations and jubilees, are not included.
+var UnitedKingdom = Jurisdiction{
+	Name: "United Kingdom",
+	holidays: []holiday{
+		fixed("New Year's Day", time.January, 1),
+		easterRelative("Good Friday", -2),
+		easterRelative("Easter Monday", 1),
+		nthWeekday("Early May Bank Holiday", time.May, time.Monday, 1),
+		nthWeekday("Spring Bank Holiday", time.May, time.Monday, -1),
+		nthWeekday("Summer Bank Holiday", time.August, time.Monday, -1),
+		fixed("Christmas Day", time.December, 25),
+		fixed("Boxing Day", time.December, 26),
+	},
+	observe: nextFreeWeekday,
+}
+
+// Germany the nationwide public holidays of Germany. Holidays which only apply
+// to some states are not included and holidays on a weekend are not moved.
+var Germany = Jurisdiction{
+	Name: "Germany",
+	holidays: []holiday{
+		fixed("New Year's Day", time.January, 1),
+		easterRelative("Good Friday", -2),
+		easterRelative("Easter Monday", 1),
+		fixed("Labour Day", time.May, 1),
+		easterRelative("Ascension Day", 39),
+		easterRelative("Whit Monday", 50),
+		fixed("German Unity Day", time.October, 3).startingIn(1990),
+		fixed("Christmas Day", time.December, 25),
+		fixed("Second Day of Christmas", time.December, 26),
+	},
+}
+
+// France the public holidays of France. Holidays on a weekend are not moved.
+var France = Jurisdiction{
+	Name: "France",
+	holidays: []holiday{
+		fixed("New Year's Day", time.January, 1),
+		easterRelative("Easter Monday", 1),
+		fixed("Labour Day", time.May, 1),
+		fixed("Victory in Europe Day", time.May, 8),
+		easterRelative("Ascension Day", 39),
+		easterRelative("Whit Monday", 50),
+		fixed("Bastille Day", time.July, 14),
+		fixed("Assumption Day", time.August, 15),
+		fixed("All Saints' Day", time.November, 1),
+		fixed("Armistice Day", time.November, 11),
+		fixed("Christmas Day", time.December, 25),
+	},
+}
+
+// Netherlands the public holidays of the Netherlands. King's Day on a Sunday is
+// observed on the Saturday before and other holidays on a weekend are not
+// moved.
+var Netherlands = Jurisdiction{
+	Name: "Netherlands",
+	holidays: []holiday{
+		fixed("New Year's Day", time.January, 1),
+		easterRelative("Good Friday", -2),
+		easterRelative("Easter Sunday", 0),
+		easterRelative("Easter Monday", 1),
+		fixed("King's Day", time.April, 27).startingIn(2014).observed(saturdayBefore),
+		easterRelative("Ascension Day", 39),
+		easterRelative("Whit Sunday", 49),
+		easterRelative("Whit Monday", 50),
+		fixed("Christmas Day", time.December, 25),
+		fixed("Second Day of Christmas", time.December, 26),
+	},
+}
@@ -0,0 +1,352 @@
+package ephemeris
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// iCalendar(RFC 5545) formats of DATE and DATE-TIME values.
+const (
+	icalDate     = "20060102"
+	icalDateTime = "20060102T150405"
+)
+
+// WriteICalendar writes the Events, such as the View of a Calendar, as the
+// VEVENTs of an iCalendar(RFC 5545) file. Only the Name, times, Status and
+// Metadata of the Events are written.
+//
+// All day Events are written with DATE values for DTSTART and DTEND, times in
+// a location from the IANA database with a TZID and all other times in UTC.
+// Open ended Events do not have a DTEND and are marked with an
+// X-EPHEMERIS-OPEN-ENDED property so they are not read as instants.
+//
+// The Description, Location, Tags and Color of the Metadata are written as
+// DESCRIPTION, LOCATION, CATEGORIES and COLOR(RFC 7986), the Attributes as
+// X-EPHEMERIS-ATTRIBUTE properties with the key in a NAME parameter.
+func WriteICalendar[T any](w io.Writer, events []EventOf[T]) error {
+	var b bytes.Buffer
+	writeICalLine(&b, "BEGIN:VCALENDAR")
+	writeICalLine(&b, "VERSION:2.0")
+	writeICalLine(&b, "PRODID:-//ephemeris//EN")
+
+	for i, e := range events {
+		writeICalLine(&b, "BEGIN:VEVENT")
+		writeICalLine(&b, "UID:"+icalUID(i, e))
+		writeICalLine(&b, "DTSTART"+formatICalTime(e.Start, e.AllDay))
+		if e.IsOpenEnded() {
+			writeICalLine(&b, icalOpenEnded+":TRUE")
+		} else {
+			writeICalLine(&b, "DTEND"+formatICalTime(e.End, e.AllDay))
+		}
+		if e.Name != "" {
+			writeICalLine(&b, "SUMMARY:"+escapeICalText(e.Name))
+		}
+		if e.Status == StatusCanceled {
+			writeICalLine(&b, "STATUS:CANCELLED")
+		}
+		if e.Metadata != nil {
+			writeICalMetadata(&b, *e.Metadata)
+		}
+		writeICalLine(&b, "END:VEVENT")
+	}
+
+	writeICalLine(&b, "END:VCALENDAR")
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// Names of the non-standard properties used for the fields of an Event which
+// iCalendar does not have.
+const (
+	icalOpenEnded = "X-EPHEMERIS-OPEN-ENDED"
+	icalAttribute = "X-EPHEMERIS-ATTRIBUTE"
+)
+
+// writeICalMetadata writes the properties for the fields of the Metadata
+// which are set, with the Attributes ordered by their key.
+func writeICalMetadata(b *bytes.Buffer, metadata Metadata) {
+	if metadata.Description != "" {
+		writeICalLine(b, "DESCRIPTION:"+escapeICalText(metadata.Description))
+	}
+	if metadata.Location != "" {
+		writeICalLine(b, "LOCATION:"+escapeICalText(metadata.Location))
+	}
+	if len(metadata.Tags) > 0 {
+		tags := make([]string, len(metadata.Tags))
+		for i, tag := range metadata.Tags {
+			tags[i] = escapeICalText(tag)
+		}
+		writeICalLine(b, "CATEGORIES:"+strings.Join(tags, ","))
+	}
+	if metadata.Color != "" {
+		writeICalLine(b, "COLOR:"+escapeICalText(metadata.Color))
+	}
+	for _, key := range slices.Sorted(maps.Keys(metadata.Attributes)) {
+		writeICalLine(b, icalAttribute+`;NAME="`+icalParamEscaper.Replace(key)+`":`+escapeICalText(metadata.Attributes[key]))
+	}
+}
+
+// icalUID a UID for the Event which is the same every time it is written.
+func icalUID[T any](i int, e EventOf[T]) string {
+	if e.RuleID != "" && !e.RecurrenceID.IsZero() {
+		return e.RuleID + "-" + e.RecurrenceID.UTC().Format(icalDateTime+"Z")
+	}
+	return fmt.Sprintf("%d-%s", i, e.Start.UTC().Format(icalDateTime+"Z"))
+}
+
+// formatICalTime the parameters and value of a DTSTART or DTEND property.
+func formatICalTime(t time.Time, allDay bool) string {
+	if allDay {
+		return ";VALUE=DATE:" + t.Format(icalDate)
+	}
+	if name := timeZoneName(t.Location()); name != "" && name != "Local" {
+		return ";TZID=" + name + ":" + t.Format(icalDateTime)
+	}
+	return ":" + t.UTC().Format(icalDateTime+"Z")
+}
+
+// writeICalLine writes a content line, folding it so no line is longer than
+// 75 octets without splitting a character.
+func writeICalLine(b *bytes.Buffer, line string) {
+	limit := 75
+	for len(line) > limit {
+		i := limit
+		for !utf8.RuneStart(line[i]) {
+			i--
+		}
+		b.WriteString(line[:i])
+		b.WriteString("\r\n ")
+		line = line[i:]
+		// The space starting the folded line counts towards its length
+		limit = 74
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+var icalTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+func escapeICalText(text string) string {
+	return icalTextEscaper.Replace(text)
+}
+
+// Parameter values can not contain quotes or line breaks so they are encoded
+// as described by RFC 6868.
+var (
+	icalParamEscaper   = strings.NewReplacer("^", "^^", "\n", "^n", `"`, "^'")
+	icalParamUnescaper = strings.NewReplacer("^^", "^", "^n", "\n", "^N", "\n", "^'", `"`)
+)
+
+var icalTextUnescaper = strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+
+func unescapeICalText(text string) string {
+	return icalTextUnescaper.Replace(text)
+}
+
+// splitICalText splits a list of text values on the commas which are not
+// escaped and unescapes each value.
+func splitICalText(text string) []string {
+	var values []string
+	start := 0
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			// Skip the escaped character
+			i++
+		case ',':
+			values = append(values, unescapeICalText(text[start:i]))
+			start = i + 1
+		}
+	}
+
+	return append(values, unescapeICalText(text[start:]))
+}
+
+// ReadICalendar reads the VEVENTs of an iCalendar(RFC 5545) file as Events.
+// Only DTSTART, DTEND, SUMMARY, STATUS and the properties written for the
+// Metadata and open ended Events by WriteICalendar are used, other properties
+// and components are ignored.
+//
+// DATE values are read as all day Events in UTC, a VEVENT with a DATE
+// DTSTART and no DTEND lasts for that day. Times with a TZID are read in that
+// location, which must be in the IANA database, and floating times are read
+// in UTC. A VEVENT with a DATE-TIME DTSTART and no DTEND is an instant unless
+// it is marked as open ended.
+func ReadICalendar(r io.Reader) ([]Event, error) {
+	lines, err := unfoldICalLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		events    []Event
+		event     *Event
+		hasEnd    bool
+		openEnded bool
+	)
+	for _, l := range lines {
+		name, params, value, ok := parseICalLine(l.text)
+		if !ok {
+			return nil, lineError(l.number, "", errors.New("invalid content line"))
+		}
+
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			event, hasEnd, openEnded = &Event{}, false, false
+			continue
+		case event == nil:
+			// Outside of a VEVENT
+			continue
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			if event.Start.IsZero() {
+				return nil, lineError(l.number, "VEVENT", errors.New("missing DTSTART"))
+			}
+			if !hasEnd && !openEnded {
+				event.End = event.Start
+				if event.AllDay {
+					event.End = event.Start.AddDate(0, 0, 1)
+				}
+			}
+			events = append(events, *event)
+			event = nil
+			continue
+		}
+
+		switch name {
+		case "DTSTART":
+			event.Start, event.AllDay, err = parseICalTime(params, value)
+		case "DTEND":
+			event.End, _, err = parseICalTime(params, value)
+			hasEnd = true
+		case "SUMMARY":
+			event.Name = unescapeICalText(value)
+		case "STATUS":
+			if strings.EqualFold(value, "CANCELLED") {
+				event.Status = StatusCanceled
+			}
+		case icalOpenEnded:
+			openEnded = strings.EqualFold(value, "TRUE")
+		case "DESCRIPTION":
+			icalMetadata(event).Description = unescapeICalText(value)
+		case "LOCATION":
+			icalMetadata(event).Location = unescapeICalText(value)
+		case "CATEGORIES":
+			metadata := icalMetadata(event)
+			metadata.Tags = append(metadata.Tags, splitICalText(value)...)
+		case "COLOR":
+			icalMetadata(event).Color = unescapeICalText(value)
+		case icalAttribute:
+			metadata := icalMetadata(event)
+			if metadata.Attributes == nil {
+				metadata.Attributes = map[string]string{}
+			}
+			metadata.Attributes[icalParamUnescaper.Replace(params["NAME"])] = unescapeICalText(value)
+		}
+		if err != nil {
+			return nil, lineError(l.number, name, err)
+		}
+	}
+
+	return events, nil
+}
+
+// icalMetadata the Metadata of the Event, which is created when it has none.
+func icalMetadata(event *Event) *Metadata {
+	if event.Metadata == nil {
+		event.Metadata = &Metadata{}
+	}
+	return event.Metadata
+}
+
+// icalLine an unfolded content line and the number of the line it starts on.
+type icalLine struct {
+	text   string
+	number int
+}
+
+// unfoldICalLines reads the content lines, joining folded lines which start
+// with a space or tab to the line before them.
+func unfoldICalLines(r io.Reader) ([]icalLine, error) {
+	var lines []icalLine
+	scanner := bufio.NewScanner(r)
+	for number := 1; scanner.Scan(); number++ {
+		text := strings.TrimSuffix(scanner.Text(), "\r")
+		switch {
+		case text == "":
+		case (text[0] == ' ' || text[0] == '\t') && len(lines) > 0:
+			lines[len(lines)-1].text += text[1:]
+		default:
+			lines = append(lines, icalLine{text: text, number: number})
+		}
+	}
+
+	return lines, scanner.Err()
+}
+
+// parseICalLine divides a content line into its upper case name, its
+// parameters and its value. Quoted parameter values may contain a colon or a
+// semicolon.
+func parseICalLine(line string) (name string, params map[string]string, value string, ok bool) {
+	colon, quoted := -1, false
+	var semicolons []int
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == ';' && !quoted {
+			semicolons = append(semicolons, i)
+		} else if r == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon <= 0 {
+		return "", nil, "", false
+	}
+
+	var parts []string
+	start := 0
+	for _, semicolon := range semicolons {
+		parts = append(parts, line[start:semicolon])
+		start = semicolon + 1
+	}
+	parts = append(parts, line[start:colon])
+	params = map[string]string{}
+	for _, param := range parts[1:] {
+		key, paramValue, _ := strings.Cut(param, "=")
+		params[strings.ToUpper(key)] = strings.Trim(paramValue, `"`)
+	}
+
+	return strings.ToUpper(parts[0]), params, line[colon+1:], true
+}
+
+// parseICalTime parses the value of a DTSTART or DTEND property, reporting if
+// it is a DATE.
+func parseICalTime(params map[string]string, value string) (time.Time, bool, error) {
+	if strings.EqualFold(params["VALUE"], "DATE") {
+		t, err := time.Parse(icalDate, value)
+		return t, true, err
+	}
+
+	if utc, ok := strings.CutSuffix(value, "Z"); ok {
+		t, err := time.Parse(icalDateTime, utc)
+		return t, false, err
+	}
+
+	loc := time.UTC
+	if tzid := params["TZID"]; tzid != "" {
+		var err error
+		if loc, err = time.LoadLocation(tzid); err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid TZID %q: %w", tzid, err)
+		}
+	}
+	t, err := time.ParseInLocation(icalDateTime, value, loc)
+	return t, false, err
+}
@@ -0,0 +1,183 @@
+package ephemeris
+
+import (
+	"bytes"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestICalendar(t *testing.T) {
+	birthday := Rule{
+		Event:             Event{Start: time.Date(2024, time.February, 13, 0, 0, 0, 0, time.UTC), End: time.Date(2024, time.February, 14, 0, 0, 0, 0, time.UTC), Name: "Birthday", AllDay: true},
+		RepeatDateAnually: 1,
+	}
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The all day Event is expanded in Tokyo but keeps its date
+	events := birthday.Expand(time.Date(2024, time.February, 1, 0, 0, 0, 0, tokyo), time.Date(2024, time.March, 1, 0, 0, 0, 0, tokyo))
+	events = append(events,
+		Event{Start: time.Date(2024, time.February, 13, 9, 0, 0, 0, newYork), End: time.Date(2024, time.February, 13, 9, 15, 0, 0, newYork), Name: "Standup; daily, with the team"},
+		Event{Start: time.Date(2024, time.February, 14, 12, 0, 0, 0, time.UTC), End: time.Date(2024, time.February, 14, 13, 0, 0, 0, time.UTC), Name: strings.Repeat("Lunch ", 20), Status: StatusCanceled},
+		Event{Start: time.Date(2024, time.February, 15, 17, 0, 0, 0, time.UTC), End: time.Date(2024, time.February, 15, 17, 0, 0, 0, time.UTC), Name: "Deploy"},
+		Event{Start: time.Date(2024, time.February, 16, 8, 0, 0, 0, time.UTC), Name: "On Call"},
+		Event{
+			Start: time.Date(2024, time.February, 19, 10, 0, 0, 0, time.UTC),
+			End:   time.Date(2024, time.February, 19, 11, 0, 0, 0, time.UTC),
+			Name:  "Review",
+			Metadata: &Metadata{
+				Description: "Quarterly review\nBring notes",
+				Location:    "Room 4; 2nd floor",
+				Tags:        []string{"work", "review, quarterly"},
+				Color:       "#ff0000",
+				Attributes:  map[string]string{"owner": "finance", "team \"a\"; ops": "yes"},
+			},
+		},
+	)
+
+	var b bytes.Buffer
+	if err := WriteICalendar(&b, events); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{
+		"DTSTART;VALUE=DATE:20240213\r\n",
+		"DTEND;VALUE=DATE:20240214\r\n",
+		"DTSTART;TZID=America/New_York:20240213T090000\r\n",
+		"SUMMARY:Standup\\; daily\\, with the team\r\n",
+		"DTSTART:20240214T120000Z\r\n",
+		"STATUS:CANCELLED\r\n",
+		"X-EPHEMERIS-OPEN-ENDED:TRUE\r\n",
+		"LOCATION:Room 4\\; 2nd floor\r\n",
+		"CATEGORIES:work,review\\, quarterly\r\n",
+		"COLOR:#ff0000\r\n",
+		"X-EPHEMERIS-ATTRIBUTE;NAME=\"owner\":finance\r\n",
+	} {
+		if !strings.Contains(b.String(), line) {
+			t.Errorf("Expected %q in %s", line, b.String())
+		}
+	}
+	for _, line := range strings.Split(b.String(), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("Expected lines to be folded at 75 octets but got %q", line)
+		}
+	}
+
+	got, err := ReadICalendar(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(events) {
+		t.Fatalf("Expected %d events but got %v", len(events), got)
+	}
+	for i, e := range got {
+		expected := events[i]
+		if e.Name != expected.Name || e.Status != expected.Status || e.AllDay != expected.AllDay {
+			t.Errorf("Expected %+v but got %+v", expected, e)
+		}
+		if !reflect.DeepEqual(e.Metadata, expected.Metadata) {
+			t.Errorf("Expected the metadata %+v but got %+v", expected.Metadata, e.Metadata)
+		}
+
+		if expected.AllDay {
+			// All day Events cover the same dates when they are read
+			if e.Start.Format(icalDate) != expected.Start.Format(icalDate) || e.End.Format(icalDate) != expected.End.Format(icalDate) {
+				t.Errorf("Expected the dates %s - %s but got %s - %s", expected.Start, expected.End, e.Start, e.End)
+			}
+			continue
+		}
+		if !e.Start.Equal(expected.Start) || !e.End.Equal(expected.End) {
+			t.Errorf("Expected %s - %s but got %s - %s", expected.Start, expected.End, e.Start, e.End)
+		}
+		if e.Start.Location().String() != expected.Start.Location().String() {
+			t.Errorf("Expected the location %s but got %s", expected.Start.Location(), e.Start.Location())
+		}
+	}
+}
+
+func TestReadICalendar(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		file     string
+		expected []Event
+	}{
+		{
+			desc: "Date Without End",
+			file: "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20240704\r\nSUMMARY:Independence Day\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n",
+			expected: []Event{
+				{Start: time.Date(2024, time.July, 4, 0, 0, 0, 0, time.UTC), End: time.Date(2024, time.July, 5, 0, 0, 0, 0, time.UTC), Name: "Independence Day", AllDay: true},
+			},
+		},
+		{
+			desc: "Several Days",
+			file: "BEGIN:VEVENT\nDTSTART;VALUE=DATE:20240701\nDTEND;VALUE=DATE:20240706\nSUMMARY:Holi\n day\nEND:VEVENT\n",
+			expected: []Event{
+				{Start: time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC), End: time.Date(2024, time.July, 6, 0, 0, 0, 0, time.UTC), Name: "Holiday", AllDay: true},
+			},
+		},
+		{
+			desc: "Instant With Time Zone",
+			file: "BEGIN:VEVENT\r\nDTSTART;TZID=\"America/New_York\":20240310T030000\r\nSUMMARY:Alarm\r\nEND:VEVENT\r\n",
+			expected: []Event{
+				{Start: time.Date(2024, time.March, 10, 3, 0, 0, 0, newYork), End: time.Date(2024, time.March, 10, 3, 0, 0, 0, newYork), Name: "Alarm"},
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			got, err := ReadICalendar(strings.NewReader(tC.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Locations are loaded again so only the times can be compared
+			equal := slices.EqualFunc(tC.expected, got, func(expected, e Event) bool {
+				return e.Start.Equal(expected.Start) && e.End.Equal(expected.End) && e.Name == expected.Name && e.AllDay == expected.AllDay
+			})
+			if !equal {
+				t.Errorf("Expected %v but got %v", tC.expected, got)
+			}
+		})
+	}
+}
+
+func TestReadICalendarErrors(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		file     string
+		expected string
+	}{
+		{
+			desc:     "Invalid Date",
+			file:     "BEGIN:VEVENT\r\nDTSTART;VALUE=DATE:2024-07-04\r\nEND:VEVENT\r\n",
+			expected: "line 2: DTSTART",
+		},
+		{
+			desc:     "Unknown Time Zone",
+			file:     "BEGIN:VEVENT\r\nDTSTART;TZID=Mars/Olympus_Mons:20240704T090000\r\nEND:VEVENT\r\n",
+			expected: "line 2: DTSTART: invalid TZID",
+		},
+		{
+			desc:     "Missing Start",
+			file:     "BEGIN:VEVENT\r\nSUMMARY:Nothing\r\nEND:VEVENT\r\n",
+			expected: "line 3: VEVENT: missing DTSTART",
+		},
+		{
+			desc:     "Invalid Content Line",
+			file:     "BEGIN:VEVENT\r\nDTSTART\r\nEND:VEVENT\r\n",
+			expected: "line 2: invalid content line",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			_, err := ReadICalendar(strings.NewReader(tC.file))
+			if err == nil || !strings.Contains(err.Error(), tC.expected) {
+				t.Errorf("Expected an error containing %q but got %v", tC.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package ephemeris
+
+import (
+	"slices"
+	"sort"
+	"time"
+)
+
+// Interval a period of time from Start(inclusive) to End(exclusive).
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Duration the length of the Interval.
+func (i Interval) Duration() time.Duration {
+	return i.End.Sub(i.Start)
+}
+
+// IntervalSet a set of times represented by Intervals, used for combining
+// periods of time such as "maintenance windows minus business hours". The
+// zero value is an empty set.
+//
+// The Intervals of an IntervalSet are ordered by their Start and never touch
+// or overlap, so each IntervalSet has exactly one representation.
+type IntervalSet struct {
+	intervals []Interval
+}
+
+// NewIntervalSet creates an IntervalSet of the times covered by any of the
+// Intervals. Intervals which do not End after their Start are empty.
+func NewIntervalSet(intervals ...Interval) IntervalSet {
+	return IntervalSet{intervals: unionIntervals(intervals)}
+}
+
+// IntervalSetFromEvents creates an IntervalSet of the times covered by any of
+// the Events, such as the Events of a Calendar's View. Instants do not cover
+// any time and open ended Events cover all the time after their Start.
+func IntervalSetFromEvents[T any](events []EventOf[T]) IntervalSet {
+	var intervals []Interval
+	for _, event := range events {
+		intervals = append(intervals, Interval{Start: event.Start, End: event.effectiveEnd()})
+	}
+	return NewIntervalSet(intervals...)
+}
+
+// Intervals returns the Intervals of the set ordered by their Start.
+func (s IntervalSet) Intervals() []Interval {
+	return slices.Clone(s.intervals)
+}
+
+// IsEmpty determines if the set does not contain any time.
+func (s IntervalSet) IsEmpty() bool {
+	return len(s.intervals) == 0
+}
+
+// Contains determines if t is within the set.
+func (s IntervalSet) Contains(t time.Time) bool {
+	// The first Interval which ends after t is the only one which may contain it
+	i := sort.Search(len(s.intervals), func(i int) bool {
+		return s.intervals[i].End.After(t)
+	})
+	return i < len(s.intervals) && !t.Before(s.intervals[i].Start)
+}
+
+// Duration the total time within the set. Sets containing open ended Events
+// are limited to the longest possible time.Duration.
+func (s IntervalSet) Duration() time.Duration {
+	var total time.Duration
+	for _, interval := range s.intervals {
+		total += interval.Duration()
+		if total < 0 {
+			return time.Duration(1<<63 - 1)
+		}
+	}
+	return total
+}
+
+// Union the times within either set.
+func (s IntervalSet) Union(other IntervalSet) IntervalSet {
+	return IntervalSet{intervals: unionIntervals(slices.Concat(s.intervals, other.intervals))}
+}
+
+// Intersect the times within both sets.
+func (s IntervalSet) Intersect(other IntervalSet) IntervalSet {
+	return IntervalSet{intervals: intersectIntervals(s.intervals, other.intervals)}
+}
+
+// Difference the times within the set which are not within the other set.
+func (s IntervalSet) Difference(other IntervalSet) IntervalSet {
+	return IntervalSet{intervals: subtractIntervals(s.intervals, other.intervals)}
+}
+
+// Complement the times within the window which are not within the set.
+func (s IntervalSet) Complement(window Interval) IntervalSet {
+	return NewIntervalSet(window).Difference(s)
+}
+
+// unionIntervals orders the Intervals by their Start and combines the ones
+// which touch or overlap. Empty Intervals are removed.
+func unionIntervals(intervals []Interval) []Interval {
+	sorted := slices.Clone(intervals)
+	slices.SortFunc(sorted, func(a, b Interval) int {
+		return a.Start.Compare(b.Start)
+	})
+
+	var union []Interval
+	for _, interval := range sorted {
+		if !interval.Start.Before(interval.End) {
+			continue
+		}
+		if last := len(union) - 1; last >= 0 && !interval.Start.After(union[last].End) {
+			if interval.End.After(union[last].End) {
+				union[last].End = interval.End
+			}
+			continue
+		}
+		union = append(union, interval)
+	}
+
+	return union
+}
+
+// intersectIntervals the times covered by both a and b, which are ordered by
+// their Start and do not overlap.
+func intersectIntervals(a, b []Interval) []Interval {
+	var intersection []Interval
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		start, end := latest(a[i].Start, b[j].Start), earliest(a[i].End, b[j].End)
+		if start.Before(end) {
+			intersection = append(intersection, Interval{Start: start, End: end})
+		}
+
+		if a[i].End.Before(b[j].End) {
+			i++
+		} else {
+			j++
+		}
+	}
+
+	return intersection
+}
+
+// subtractIntervals the times covered by a which are not covered by b, which
+// are ordered by their Start and do not overlap.
+func subtractIntervals(a, b []Interval) []Interval {
+	var difference []Interval
+	j := 0
+	for _, interval := range a {
+		start := interval.Start
+		for ; j < len(b) && b[j].Start.Before(interval.End); j++ {
+			if b[j].End.After(start) {
+				if b[j].Start.After(start) {
+					difference = append(difference, Interval{Start: start, End: b[j].Start})
+				}
+				start = latest(start, b[j].End)
+			}
+			if b[j].End.After(interval.End) {
+				// The rest of b[j] may overlap the next Interval
+				break
+			}
+		}
+		if start.Before(interval.End) {
+			difference = append(difference, Interval{Start: start, End: interval.End})
+		}
+	}
+
+	return difference
+}
+
+func earliest(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
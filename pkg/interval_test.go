@@ -0,0 +1,139 @@
+package ephemeris
+
+import (
+	"slices"
+	"testing"
+	"testing/quick"
+	"time"
+)
+
+func TestIntervalSet(t *testing.T) {
+	at := func(hour int) time.Time {
+		return time.Date(2024, time.March, 4, hour, 0, 0, 0, time.UTC)
+	}
+	interval := func(start, end int) Interval {
+		return Interval{Start: at(start), End: at(end)}
+	}
+
+	maintenance := NewIntervalSet(interval(2, 4), interval(16, 20))
+	businessHours := NewIntervalSet(interval(9, 17))
+	day := interval(0, 24)
+
+	testCases := []struct {
+		desc     string
+		got      IntervalSet
+		expected []Interval
+	}{
+		{
+			desc:     "Union",
+			got:      maintenance.Union(businessHours),
+			expected: []Interval{interval(2, 4), interval(9, 20)},
+		},
+		{
+			desc:     "Intersect",
+			got:      maintenance.Intersect(businessHours),
+			expected: []Interval{interval(16, 17)},
+		},
+		{
+			desc:     "Difference",
+			got:      maintenance.Difference(businessHours),
+			expected: []Interval{interval(2, 4), interval(17, 20)},
+		},
+		{
+			desc:     "Complement",
+			got:      maintenance.Complement(day),
+			expected: []Interval{interval(0, 2), interval(4, 16), interval(20, 24)},
+		},
+		{
+			desc:     "Touching Intervals Are Combined",
+			got:      NewIntervalSet(interval(9, 12), interval(12, 13), interval(11, 12), interval(15, 15)),
+			expected: []Interval{interval(9, 13)},
+		},
+		{
+			desc: "From Events",
+			got: IntervalSetFromEvents([]Event{
+				{Start: at(9), End: at(10), Name: "Standup"},
+				{Start: at(12), End: at(12), Name: "Alarm"},
+				{Start: at(20), Name: "Night Mode"},
+			}).Intersect(NewIntervalSet(day)),
+			expected: []Interval{interval(9, 10), interval(20, 24)},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			if got := tC.got.Intervals(); !slices.Equal(tC.expected, got) {
+				t.Errorf("Expected %v but got %v", tC.expected, got)
+			}
+		})
+	}
+
+	if got := maintenance.Duration(); got != 6*time.Hour {
+		t.Errorf("Expected a duration of 6h but got %s", got)
+	}
+	if !maintenance.Contains(at(2)) || maintenance.Contains(at(4)) || maintenance.Contains(at(10)) {
+		t.Errorf("Expected only the times within the maintenance windows to be contained")
+	}
+}
+
+func TestIntervalSet_Property(t *testing.T) {
+	// Small values result in many touching and overlapping Intervals
+	newSet := func(points []int8) IntervalSet {
+		var intervals []Interval
+		for i := 0; i+1 < len(points); i += 2 {
+			intervals = append(intervals, Interval{Start: time.UnixMilli(int64(points[i])), End: time.UnixMilli(int64(points[i+1]))})
+		}
+		return NewIntervalSet(intervals...)
+	}
+
+	isNormalized := func(s IntervalSet) bool {
+		for i, interval := range s.intervals {
+			if !interval.Start.Before(interval.End) {
+				return false
+			}
+			if i > 0 && !s.intervals[i-1].End.Before(interval.Start) {
+				return false
+			}
+		}
+		return true
+	}
+
+	f := func(aPoints, bPoints []int8, windowStart, windowEnd int8) bool {
+		a, b := newSet(aPoints), newSet(bPoints)
+		window := Interval{Start: time.UnixMilli(int64(windowStart)), End: time.UnixMilli(int64(windowEnd))}
+
+		union, intersection, difference, complement := a.Union(b), a.Intersect(b), a.Difference(b), a.Complement(window)
+		for _, s := range []IntervalSet{a, b, union, intersection, difference, complement} {
+			if !isNormalized(s) {
+				t.Logf("expected intervals to be ordered without touching or overlapping: %v", s.intervals)
+				return false
+			}
+		}
+
+		if union.Duration()+intersection.Duration() != a.Duration()+b.Duration() {
+			t.Log("expected the union and intersection to have the same duration as both sets")
+			return false
+		}
+		if difference.Duration()+intersection.Duration() != a.Duration() {
+			t.Log("expected the difference and intersection to have the same duration as the set")
+			return false
+		}
+
+		for point := int64(-130); point <= 130; point++ {
+			p := time.UnixMilli(point)
+			inA, inB, inWindow := a.Contains(p), b.Contains(p), !p.Before(window.Start) && p.Before(window.End)
+			if union.Contains(p) != (inA || inB) ||
+				intersection.Contains(p) != (inA && inB) ||
+				difference.Contains(p) != (inA && !inB) ||
+				complement.Contains(p) != (inWindow && !inA) {
+				t.Logf("expected the sets to contain the right times at %d", point)
+				return false
+			}
+		}
+
+		return true
+	}
+
+	if err := quick.Check(f, &quick.Config{MaxCount: 10_000}); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,609 @@
+package ephemeris
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// SchemaVersion the version of the JSON representation of a Calendar which is
+// written by MarshalJSON. Calendars without a version are read as the current
+// version and newer versions are rejected.
+const SchemaVersion = 1
+
+// JSONSchema the JSON Schema of the JSON representation of a Calendar, for
+// services which author Calendars without this package.
+//
+//go:embed calendar.schema.json
+var JSONSchema string
+
+// The JSON representation uses RFC 3339 timestamps. The location of the times
+// of an Event or Rule is kept with a timeZone name, so Rules which repeat by the
+// clock keep following daylight saving time, and times are converted to it
+// when they are read. Durations are written as ISO 8601 durations, such as
+// PT1H30M, and either those or Go durations, such as 1h30m, can be read. Zero
+// value fields are omitted and unknown fields are an error.
+
+type jsonCalendar[T any] struct {
+	SchemaVersion     int               `json:"schemaVersion"`
+	Name              string            `json:"name,omitempty"`
+	Entries           []RuleOf[T]       `json:"entries,omitempty"`
+	Merge             string            `json:"merge,omitempty"`
+	MinimumDuration   string            `json:"minimumDuration,omitempty"`
+	AbsorbShortEvents bool              `json:"absorbShortEvents,omitempty"`
+	Idle              *EventOf[T]       `json:"idle,omitempty"`
+	WorkingHours      *jsonWorkingHours `json:"workingHours,omitempty"`
+	Tracks            []Track           `json:"tracks,omitempty"`
+}
+
+type jsonWorkingHours struct {
+	TimeZone string                     `json:"timeZone,omitempty"`
+	Days     map[string][]jsonDayWindow `json:"days,omitempty"`
+}
+
+type jsonDayWindow struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+type jsonEvent[T any] struct {
+	Start        string    `json:"start,omitempty"`
+	End          string    `json:"end,omitempty"`
+	TimeZone     string    `json:"timeZone,omitempty"`
+	Name         string    `json:"name,omitempty"`
+	Payload      *T        `json:"payload,omitempty"`
+	Status       string    `json:"status,omitempty"`
+	RecurrenceID string    `json:"recurrenceId,omitempty"`
+	RuleID       string    `json:"ruleId,omitempty"`
+	Truncated    bool      `json:"truncated,omitempty"`
+	Split        bool      `json:"split,omitempty"`
+	Layer        string    `json:"layer,omitempty"`
+	Track        string    `json:"track,omitempty"`
+	AllDay       bool      `json:"allDay,omitempty"`
+	Metadata     *Metadata `json:"metadata,omitempty"`
+}
+
+type jsonRule[T any] struct {
+	jsonEvent[T]
+	ID                       string       `json:"id,omitempty"`
+	RepeatDuration           string       `json:"repeatDuration,omitempty"`
+	RepeatDateAnually        int          `json:"repeatDateAnnually,omitempty"`
+	RepeatWeekly             int          `json:"repeatWeekly,omitempty"`
+	RepeatDayOfMonthMonthly  int          `json:"repeatDayOfMonthMonthly,omitempty"`
+	RepeatDaily              int          `json:"repeatDaily,omitempty"`
+	RepeatHourly             int          `json:"repeatHourly,omitempty"`
+	RepeatMinutely           int          `json:"repeatMinutely,omitempty"`
+	RepeatEaster             string       `json:"repeatEaster,omitempty"`
+	EasterOffsetDays         int          `json:"easterOffsetDays,omitempty"`
+	ByHour                   []int        `json:"byHour,omitempty"`
+	ByMinute                 []int        `json:"byMinute,omitempty"`
+	Cron                     string       `json:"cron,omitempty"`
+	RepeatBusinessDaily      int          `json:"repeatBusinessDaily,omitempty"`
+	RepeatBusinessDayOfMonth int          `json:"repeatBusinessDayOfMonth,omitempty"`
+	Holidays                 *Calendar    `json:"holidays,omitempty"`
+	Anchor                   *jsonAnchor  `json:"anchor,omitempty"`
+	RepeatForwardUntil       string       `json:"repeatForwardUntil,omitempty"`
+	RepeatBackwardUntil      string       `json:"repeatBackwardUntil,omitempty"`
+	Skip                     []string     `json:"skip,omitempty"`
+	Canceled                 []string     `json:"canceled,omitempty"`
+	Overrides                []EventOf[T] `json:"overrides,omitempty"`
+}
+
+type jsonAnchor struct {
+	RuleID   string `json:"ruleId"`
+	Offset   string `json:"offset,omitempty"`
+	FromEnd  bool   `json:"fromEnd,omitempty"`
+	Duration string `json:"duration,omitempty"`
+}
+
+var (
+	statusNames   = map[Status]string{StatusConfirmed: "", StatusCanceled: "canceled"}
+	computusNames = map[Computus]string{ComputusNone: "", ComputusWestern: "western", ComputusOrthodox: "orthodox"}
+	mergeByNames  = map[MergeBy]string{MergeNone: "", MergeByName: "name", MergeByRuleID: "ruleId", MergeByPayload: "payload"}
+	weekdayNames  = map[time.Weekday]string{
+		time.Sunday: "sunday", time.Monday: "monday", time.Tuesday: "tuesday", time.Wednesday: "wednesday",
+		time.Thursday: "thursday", time.Friday: "friday", time.Saturday: "saturday",
+	}
+)
+
+// MarshalJSON writes the Calendar with the current SchemaVersion.
+func (c CalendarOf[T]) MarshalJSON() ([]byte, error) {
+	merge, err := enumName(c.Merge, mergeByNames, "merge")
+	if err != nil {
+		return nil, err
+	}
+
+	j := jsonCalendar[T]{
+		SchemaVersion:     SchemaVersion,
+		Name:              c.Name,
+		Entries:           c.Entries,
+		Merge:             merge,
+		MinimumDuration:   formatOptionalDuration(c.MinimumDuration),
+		AbsorbShortEvents: c.AbsorbShortEvents,
+		Idle:              c.Idle,
+		Tracks:            c.Tracks,
+	}
+	if c.WorkingHours != nil {
+		hours := &jsonWorkingHours{TimeZone: timeZoneName(c.WorkingHours.Location), Days: map[string][]jsonDayWindow{}}
+		for day, windows := range c.WorkingHours.Days {
+			for _, window := range windows {
+				hours.Days[weekdayNames[time.Weekday(day)]] = append(hours.Days[weekdayNames[time.Weekday(day)]], jsonDayWindow{
+					Start: formatDuration(window.Start),
+					End:   formatDuration(window.End),
+				})
+			}
+		}
+		j.WorkingHours = hours
+	}
+
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON reads a Calendar written by MarshalJSON or matching JSONSchema.
+func (c *CalendarOf[T]) UnmarshalJSON(data []byte) error {
+	var j jsonCalendar[T]
+	if err := decodeStrict(data, &j); err != nil {
+		return err
+	}
+	if j.SchemaVersion > SchemaVersion {
+		return fmt.Errorf("unsupported schema version %d, the latest supported version is %d", j.SchemaVersion, SchemaVersion)
+	}
+
+	merge, err := parseEnum(j.Merge, mergeByNames, "merge")
+	if err != nil {
+		return err
+	}
+	minimumDuration, err := parseOptionalDuration(j.MinimumDuration, "minimumDuration")
+	if err != nil {
+		return err
+	}
+
+	calendar := CalendarOf[T]{
+		Name:              j.Name,
+		Entries:           j.Entries,
+		Merge:             merge,
+		MinimumDuration:   minimumDuration,
+		AbsorbShortEvents: j.AbsorbShortEvents,
+		Idle:              j.Idle,
+		Tracks:            j.Tracks,
+	}
+	if j.WorkingHours != nil {
+		loc, err := loadTimeZone(j.WorkingHours.TimeZone)
+		if err != nil {
+			return err
+		}
+
+		hours := &WorkingHours{Location: loc}
+		for name, windows := range j.WorkingHours.Days {
+			day, err := parseEnum(name, weekdayNames, "working hours day")
+			if err != nil {
+				return err
+			}
+			for _, window := range windows {
+				start, err := parseDuration(window.Start)
+				if err != nil {
+					return fmt.Errorf("invalid working hours start: %w", err)
+				}
+				end, err := parseDuration(window.End)
+				if err != nil {
+					return fmt.Errorf("invalid working hours end: %w", err)
+				}
+				hours.Days[day] = append(hours.Days[day], DayWindow{Start: start, End: end})
+			}
+		}
+		calendar.WorkingHours = hours
+	}
+
+	*c = calendar
+	return nil
+}
+
+// MarshalJSON writes the Event with its times in the location of its Start.
+func (e EventOf[T]) MarshalJSON() ([]byte, error) {
+	j, err := newJSONEvent(e)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON reads an Event written by MarshalJSON.
+func (e *EventOf[T]) UnmarshalJSON(data []byte) error {
+	var j jsonEvent[T]
+	if err := decodeStrict(data, &j); err != nil {
+		return err
+	}
+
+	loc, err := loadTimeZone(j.TimeZone)
+	if err != nil {
+		return err
+	}
+	event, err := j.event(loc)
+	if err != nil {
+		return err
+	}
+
+	*e = event
+	return nil
+}
+
+func newJSONEvent[T any](e EventOf[T]) (jsonEvent[T], error) {
+	status, err := enumName(e.Status, statusNames, "status")
+	if err != nil {
+		return jsonEvent[T]{}, err
+	}
+
+	j := jsonEvent[T]{
+		Start:        formatTime(e.Start),
+		End:          formatTime(e.End),
+		Name:         e.Name,
+		Status:       status,
+		RecurrenceID: formatTime(e.RecurrenceID),
+		RuleID:       e.RuleID,
+		Truncated:    e.Truncated,
+		Split:        e.Split,
+		Layer:        e.Layer,
+		Track:        e.Track,
+		AllDay:       e.AllDay,
+		Metadata:     e.Metadata,
+	}
+	if !e.Start.IsZero() {
+		j.TimeZone = timeZoneName(e.Start.Location())
+	}
+	if !reflect.ValueOf(&e.Payload).Elem().IsZero() {
+		j.Payload = &e.Payload
+	}
+
+	return j, nil
+}
+
+// event converts the JSON representation to an Event with its times in loc.
+func (j jsonEvent[T]) event(loc *time.Location) (EventOf[T], error) {
+	status, err := parseEnum(j.Status, statusNames, "status")
+	if err != nil {
+		return EventOf[T]{}, err
+	}
+
+	e := EventOf[T]{
+		Name:      j.Name,
+		Status:    status,
+		RuleID:    j.RuleID,
+		Truncated: j.Truncated,
+		Split:     j.Split,
+		Layer:     j.Layer,
+		Track:     j.Track,
+		AllDay:    j.AllDay,
+		Metadata:  j.Metadata,
+	}
+	if j.Payload != nil {
+		e.Payload = *j.Payload
+	}
+
+	for _, field := range []struct {
+		name  string
+		value string
+		time  *time.Time
+	}{
+		{"start", j.Start, &e.Start},
+		{"end", j.End, &e.End},
+		{"recurrenceId", j.RecurrenceID, &e.RecurrenceID},
+	} {
+		if *field.time, err = parseTime(field.value, loc); err != nil {
+			return EventOf[T]{}, fmt.Errorf("invalid %s: %w", field.name, err)
+		}
+	}
+
+	return e, nil
+}
+
+// MarshalJSON writes the Rule with the fields of its Event alongside the
+// fields of the Rule, with all of its times in the location of its Start.
+func (r RuleOf[T]) MarshalJSON() ([]byte, error) {
+	event, err := newJSONEvent(r.event())
+	if err != nil {
+		return nil, err
+	}
+	repeatEaster, err := enumName(r.RepeatEaster, computusNames, "repeatEaster")
+	if err != nil {
+		return nil, err
+	}
+
+	j := jsonRule[T]{
+		jsonEvent:                event,
+		ID:                       r.ID,
+		RepeatDuration:           formatOptionalDuration(r.RepeatDuration),
+		RepeatDateAnually:        r.RepeatDateAnually,
+		RepeatWeekly:             r.RepeatWeekly,
+		RepeatDayOfMonthMonthly:  r.RepeatDayOfMonthMonthly,
+		RepeatDaily:              r.RepeatDaily,
+		RepeatHourly:             r.RepeatHourly,
+		RepeatMinutely:           r.RepeatMinutely,
+		RepeatEaster:             repeatEaster,
+		EasterOffsetDays:         r.EasterOffsetDays,
+		ByHour:                   r.ByHour,
+		ByMinute:                 r.ByMinute,
+		Cron:                     r.Cron,
+		RepeatBusinessDaily:      r.RepeatBusinessDaily,
+		RepeatBusinessDayOfMonth: r.RepeatBusinessDayOfMonth,
+		Holidays:                 r.Holidays,
+		RepeatForwardUntil:       formatTime(r.RepeatForwardUntil),
+		RepeatBackwardUntil:      formatTime(r.RepeatBackwardUntil),
+		Overrides:                r.Overrides,
+	}
+	for _, skip := range r.Skip {
+		j.Skip = append(j.Skip, formatTime(skip))
+	}
+	for _, canceled := range r.Canceled {
+		j.Canceled = append(j.Canceled, formatTime(canceled))
+	}
+	if r.Anchor != nil {
+		j.Anchor = &jsonAnchor{
+			RuleID:   r.Anchor.RuleID,
+			Offset:   formatOptionalDuration(r.Anchor.Offset),
+			FromEnd:  r.Anchor.FromEnd,
+			Duration: formatOptionalDuration(r.Anchor.Duration),
+		}
+	}
+
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON reads a Rule written by MarshalJSON.
+func (r *RuleOf[T]) UnmarshalJSON(data []byte) error {
+	var j jsonRule[T]
+	if err := decodeStrict(data, &j); err != nil {
+		return err
+	}
+
+	loc, err := loadTimeZone(j.TimeZone)
+	if err != nil {
+		return err
+	}
+	event, err := j.jsonEvent.event(loc)
+	if err != nil {
+		return err
+	}
+	repeatEaster, err := parseEnum(j.RepeatEaster, computusNames, "repeatEaster")
+	if err != nil {
+		return err
+	}
+	repeatDuration, err := parseOptionalDuration(j.RepeatDuration, "repeatDuration")
+	if err != nil {
+		return err
+	}
+
+	rule := RuleOf[T]{
+		Event:                    withPayload(event, struct{}{}),
+		Payload:                  event.Payload,
+		ID:                       j.ID,
+		RepeatDuration:           repeatDuration,
+		RepeatDateAnually:        j.RepeatDateAnually,
+		RepeatWeekly:             j.RepeatWeekly,
+		RepeatDayOfMonthMonthly:  j.RepeatDayOfMonthMonthly,
+		RepeatDaily:              j.RepeatDaily,
+		RepeatHourly:             j.RepeatHourly,
+		RepeatMinutely:           j.RepeatMinutely,
+		RepeatEaster:             repeatEaster,
+		EasterOffsetDays:         j.EasterOffsetDays,
+		ByHour:                   j.ByHour,
+		ByMinute:                 j.ByMinute,
+		Cron:                     j.Cron,
+		RepeatBusinessDaily:      j.RepeatBusinessDaily,
+		RepeatBusinessDayOfMonth: j.RepeatBusinessDayOfMonth,
+		Holidays:                 j.Holidays,
+		Overrides:                j.Overrides,
+	}
+	if rule.RepeatForwardUntil, err = parseTime(j.RepeatForwardUntil, loc); err != nil {
+		return fmt.Errorf("invalid repeatForwardUntil: %w", err)
+	}
+	if rule.RepeatBackwardUntil, err = parseTime(j.RepeatBackwardUntil, loc); err != nil {
+		return fmt.Errorf("invalid repeatBackwardUntil: %w", err)
+	}
+	if rule.Skip, err = parseTimes(j.Skip, loc); err != nil {
+		return fmt.Errorf("invalid skip: %w", err)
+	}
+	if rule.Canceled, err = parseTimes(j.Canceled, loc); err != nil {
+		return fmt.Errorf("invalid canceled: %w", err)
+	}
+	if j.Anchor != nil {
+		rule.Anchor = &Anchor{RuleID: j.Anchor.RuleID, FromEnd: j.Anchor.FromEnd}
+		if rule.Anchor.Offset, err = parseOptionalDuration(j.Anchor.Offset, "anchor offset"); err != nil {
+			return err
+		}
+		if rule.Anchor.Duration, err = parseOptionalDuration(j.Anchor.Duration, "anchor duration"); err != nil {
+			return err
+		}
+	}
+
+	*r = rule
+	return nil
+}
+
+// decodeStrict decodes the JSON into v where unknown fields are an error.
+func decodeStrict(data []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
+
+func enumName[E comparable](value E, names map[E]string, field string) (string, error) {
+	name, ok := names[value]
+	if !ok {
+		return "", fmt.Errorf("invalid %s %v", field, value)
+	}
+	return name, nil
+}
+
+func parseEnum[E comparable](name string, names map[E]string, field string) (E, error) {
+	for value, valueName := range names {
+		if valueName == name {
+			return value, nil
+		}
+	}
+
+	var zero E
+	return zero, fmt.Errorf("invalid %s %q", field, name)
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+// parseTime parses an RFC 3339 timestamp and converts it to loc, an empty
+// value is the zero time.
+func parseTime(value string, loc *time.Location) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if loc != nil {
+		t = t.In(loc)
+	}
+	return t, nil
+}
+
+func parseTimes(values []string, loc *time.Location) ([]time.Time, error) {
+	var times []time.Time
+	for _, value := range values {
+		t, err := parseTime(value, loc)
+		if err != nil {
+			return nil, err
+		}
+		times = append(times, t)
+	}
+	return times, nil
+}
+
+// timeZoneName the name of the location to write, which is empty for UTC and
+// locations with a fixed offset as the timestamps already describe them.
+func timeZoneName(loc *time.Location) string {
+	if loc == nil || loc == time.UTC || loc.String() == "UTC" {
+		return ""
+	}
+	if _, err := time.LoadLocation(loc.String()); err != nil {
+		// Locations created with time.FixedZone have a name which is not in
+		// the IANA database, so it could not be loaded again
+		return ""
+	}
+	return loc.String()
+}
+
+// loadTimeZone loads a location by its IANA name, an empty name has no
+// location.
+func loadTimeZone(name string) (*time.Location, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timeZone %q: %w", name, err)
+	}
+	return loc, nil
+}
+
+// formatDuration writes the duration as an ISO 8601 duration using hours,
+// minutes, and seconds, such as PT1H30M.
+func formatDuration(d time.Duration) string {
+	if d == 0 {
+		return "PT0S"
+	}
+
+	var b strings.Builder
+	if d < 0 {
+		b.WriteString("-")
+		d = -d
+	}
+	b.WriteString("PT")
+
+	hours, minutes := d/time.Hour, d%time.Hour/time.Minute
+	seconds, nanoseconds := d%time.Minute/time.Second, d%time.Second
+	if hours > 0 {
+		fmt.Fprintf(&b, "%dH", hours)
+	}
+	if minutes > 0 {
+		fmt.Fprintf(&b, "%dM", minutes)
+	}
+	if nanoseconds > 0 {
+		fmt.Fprintf(&b, "%d.%sS", seconds, strings.TrimRight(fmt.Sprintf("%09d", nanoseconds), "0"))
+	} else if seconds > 0 {
+		fmt.Fprintf(&b, "%dS", seconds)
+	}
+
+	return b.String()
+}
+
+// formatOptionalDuration like formatDuration where a zero duration is omitted.
+func formatOptionalDuration(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return formatDuration(d)
+}
+
+// isoDuration matches ISO 8601 durations with weeks, days, hours, minutes, and
+// seconds. Years and months do not have a fixed length so they are not
+// supported.
+var isoDuration = regexp.MustCompile(`^([-+])?P(?:([0-9.,]+)W)?(?:([0-9.,]+)D)?(?:T(?:([0-9.,]+)H)?(?:([0-9.,]+)M)?(?:([0-9.,]+)S)?)?$`)
+
+// parseDuration parses an ISO 8601 duration, such as PT1H30M, or a Go
+// duration, such as 1h30m. Days in ISO 8601 durations are 24 hours.
+func parseDuration(value string) (time.Duration, error) {
+	if !strings.HasPrefix(strings.TrimLeft(value, "+-"), "P") {
+		return time.ParseDuration(value)
+	}
+
+	matches := isoDuration.FindStringSubmatch(value)
+	if matches == nil || value == "P" || strings.HasSuffix(value, "T") || slices.Equal(matches[2:], make([]string, 5)) {
+		return 0, fmt.Errorf("invalid duration %q, expected an ISO 8601 duration with weeks, days, hours, minutes, or seconds", value)
+	}
+
+	var total time.Duration
+	units := []struct {
+		suffix     string
+		multiplier time.Duration
+	}{{"h", 7 * 24}, {"h", 24}, {"h", 1}, {"m", 1}, {"s", 1}}
+	for i, unit := range units {
+		component := strings.ReplaceAll(matches[i+2], ",", ".")
+		if component == "" {
+			continue
+		}
+
+		d, err := time.ParseDuration(component + unit.suffix)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q: %w", value, err)
+		}
+		total += d * unit.multiplier
+	}
+
+	if matches[1] == "-" {
+		total = -total
+	}
+	return total, nil
+}
+
+// parseOptionalDuration like parseDuration where an empty value is zero.
+func parseOptionalDuration(value, field string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	d, err := parseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", field, err)
+	}
+	return d, nil
+}
@@ -0,0 +1,295 @@
+package ephemeris
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCalendarJSON(t *testing.T) {
+	at := func(day, hour int) time.Time {
+		return time.Date(2024, time.March, day, hour, 0, 0, 0, newYork)
+	}
+
+	hours := WeekdayHours(9*time.Hour, 17*time.Hour+30*time.Minute, newYork)
+	holidays := Calendar{Name: "Holidays", Entries: []Rule{{Event: Event{Start: at(8, 0), End: at(9, 0), Name: "Holiday", AllDay: true}}}}
+	calendar := Calendar{
+		Name: "Team",
+		Entries: []Rule{
+			{
+				ID: "standup",
+				Event: Event{
+					Start:    at(4, 9),
+					End:      at(4, 9).Add(15 * time.Minute),
+					Name:     "Standup",
+					Track:    "team",
+					Metadata: &Metadata{Description: "Daily status", Tags: []string{"team"}, Attributes: map[string]string{"room": "1"}},
+				},
+				RepeatBusinessDaily: 1,
+				Holidays:            &holidays,
+				RepeatBackwardUntil: at(4, 9),
+				RepeatForwardUntil:  at(29, 9),
+				Skip:                []time.Time{at(5, 9)},
+				Canceled:            []time.Time{at(6, 9)},
+				Overrides: []Event{
+					{RecurrenceID: at(7, 9), Start: at(7, 10), Name: "Late Standup"},
+				},
+			},
+			{
+				ID:     "retro",
+				Event:  Event{Name: "Retro"},
+				Anchor: &Anchor{RuleID: "standup", Offset: 2 * time.Hour, Duration: 90 * time.Minute},
+			},
+			{
+				Event:          Event{Start: at(4, 12), End: at(4, 13), Name: "Lunch", Status: StatusCanceled},
+				RepeatDuration: 24 * time.Hour,
+				ByHour:         []int{12},
+			},
+			{
+				Event:            Event{Start: at(4, 0), End: at(5, 0), Name: "Easter Monday"},
+				RepeatEaster:     ComputusOrthodox,
+				EasterOffsetDays: 1,
+			},
+			{Event: Event{Start: at(4, 22), Name: "Night Mode"}, Cron: "0 22 * * *"},
+		},
+		Merge:             MergeByName,
+		MinimumDuration:   5 * time.Minute,
+		AbsorbShortEvents: true,
+		Idle:              &Event{Name: "Free"},
+		WorkingHours:      &hours,
+		Tracks:            []Track{{Name: "team", Capacity: 2}},
+	}
+
+	data, err := json.Marshal(calendar)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded Calendar
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	again, err := json.Marshal(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != string(again) {
+		t.Errorf("Expected the JSON to be the same after a round trip\n%s\n%s", data, again)
+	}
+
+	if decoded.Entries[0].Start.Location().String() != "America/New_York" {
+		t.Errorf("Expected the time zone to be kept but got %s", decoded.Entries[0].Start.Location())
+	}
+
+	// Events are compared as JSON since the decoded times have a different,
+	// but equivalent, location
+	for _, c := range []*Calendar{&calendar, &decoded} {
+		c.Idle, c.Merge = nil, MergeNone
+	}
+	expected, err := calendar.View(at(1, 0), at(31, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := decoded.View(at(1, 0), at(31, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedJSON, _ := json.Marshal(expected)
+	gotJSON, _ := json.Marshal(got)
+	if len(expected) == 0 || string(expectedJSON) != string(gotJSON) {
+		t.Errorf("Expected the decoded calendar to have the same view\n%s\n%s", expectedJSON, gotJSON)
+	}
+}
+
+func TestRuleJSON(t *testing.T) {
+	rule := Rule{
+		ID:                 "standup",
+		Event:              Event{Start: time.Date(2024, time.March, 4, 9, 0, 0, 0, newYork), End: time.Date(2024, time.March, 4, 9, 15, 0, 0, newYork), Name: "Standup"},
+		RepeatDaily:        1,
+		RepeatForwardUntil: time.Date(2024, time.March, 29, 9, 0, 0, 0, newYork),
+		Anchor:             &Anchor{RuleID: "other", Offset: -90 * time.Minute},
+	}
+
+	got, err := json.Marshal(rule)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"start":"2024-03-04T09:00:00-05:00","end":"2024-03-04T09:15:00-05:00","timeZone":"America/New_York","name":"Standup","id":"standup","repeatDaily":1,"anchor":{"ruleId":"other","offset":"-PT1H30M"},"repeatForwardUntil":"2024-03-29T09:00:00-04:00"}`
+	if string(got) != expected {
+		t.Errorf("Expected %s but got %s", expected, got)
+	}
+}
+
+func TestRuleJSONFixedZone(t *testing.T) {
+	kiribati := time.FixedZone("UTC+14", 14*60*60)
+	rule := Rule{
+		Event:       Event{Start: time.Date(2024, time.March, 4, 9, 0, 0, 0, kiribati), End: time.Date(2024, time.March, 4, 10, 0, 0, 0, kiribati), Name: "Standup"},
+		RepeatDaily: 1,
+	}
+
+	data, err := json.Marshal(rule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "timeZone") {
+		t.Errorf("Expected no timeZone for a fixed zone but got %s", data)
+	}
+
+	var got Rule
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	viewStart := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	viewEnd := time.Date(2024, time.March, 8, 0, 0, 0, 0, time.UTC)
+	expected := rule.Expand(viewStart, viewEnd)
+	events := got.Expand(viewStart, viewEnd)
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events but got %d", len(expected), len(events))
+	}
+	for i, event := range events {
+		if !event.Start.Equal(expected[i].Start) || !event.End.Equal(expected[i].End) {
+			t.Errorf("Expected %s - %s but got %s - %s", expected[i].Start, expected[i].End, event.Start, event.End)
+		}
+		if _, offset := event.Start.Zone(); offset != 14*60*60 {
+			t.Errorf("Expected an offset of +14:00 but got %s", event.Start)
+		}
+	}
+}
+
+func TestPayloadJSON(t *testing.T) {
+	type setpoint struct {
+		Celsius float64 `json:"celsius"`
+	}
+
+	calendar := CalendarOf[setpoint]{
+		Entries: []RuleOf[setpoint]{
+			{Event: Event{Start: time.Date(2024, time.March, 4, 7, 0, 0, 0, time.UTC), Name: "Comfort"}, Payload: setpoint{Celsius: 21}},
+			{Event: Event{Start: time.Date(2024, time.March, 4, 22, 0, 0, 0, time.UTC), Name: "Off"}},
+		},
+	}
+
+	data, err := json.Marshal(calendar)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"schemaVersion":1,"entries":[{"start":"2024-03-04T07:00:00Z","name":"Comfort","payload":{"celsius":21}},{"start":"2024-03-04T22:00:00Z","name":"Off"}]}`
+	if string(data) != expected {
+		t.Errorf("Expected %s but got %s", expected, data)
+	}
+
+	var decoded CalendarOf[setpoint]
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Entries[0].Payload.Celsius != 21 || decoded.Entries[1].Payload.Celsius != 0 {
+		t.Errorf("Expected the payloads to be decoded but got %v", decoded.Entries)
+	}
+}
+
+func TestCalendarJSONErrors(t *testing.T) {
+	testCases := []struct {
+		desc string
+		json string
+		err  string
+	}{
+		{desc: "Unknown Field", json: `{"entries":[{"name":"Standup","repeatDialy":1}]}`, err: "repeatDialy"},
+		{desc: "Newer Schema Version", json: `{"schemaVersion":2}`, err: "unsupported schema version 2"},
+		{desc: "Invalid Time", json: `{"entries":[{"start":"2024-03-04 09:00"}]}`, err: "invalid start"},
+		{desc: "Invalid Time Zone", json: `{"entries":[{"timeZone":"Mars/Olympus_Mons"}]}`, err: "invalid timeZone"},
+		{desc: "Invalid Duration", json: `{"minimumDuration":"P1M"}`, err: "invalid minimumDuration"},
+		{desc: "Invalid Enum", json: `{"merge":"color"}`, err: `invalid merge "color"`},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			var calendar Calendar
+			err := json.Unmarshal([]byte(tC.json), &calendar)
+			if err == nil || !strings.Contains(err.Error(), tC.err) {
+				t.Errorf("Expected an error containing %q but got %v", tC.err, err)
+			}
+		})
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected time.Duration
+		err      bool
+	}{
+		{value: "PT1H30M", expected: 90 * time.Minute},
+		{value: "1h30m", expected: 90 * time.Minute},
+		{value: "P1DT2H", expected: 26 * time.Hour},
+		{value: "P1W", expected: 7 * 24 * time.Hour},
+		{value: "P0.5D", expected: 12 * time.Hour},
+		{value: "-PT15M", expected: -15 * time.Minute},
+		{value: "PT1,5S", expected: 1500 * time.Millisecond},
+		{value: "PT1.000000001S", expected: time.Second + time.Nanosecond},
+		{value: "P1Y", err: true},
+		{value: "P", err: true},
+		{value: "PT", err: true},
+		{value: "soon", err: true},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.value, func(t *testing.T) {
+			got, err := parseDuration(tC.value)
+			if (err != nil) != tC.err {
+				t.Fatalf("Expected an error %t but got %v", tC.err, err)
+			}
+			if got != tC.expected {
+				t.Errorf("Expected %s but got %s", tC.expected, got)
+			}
+
+			if !tC.err {
+				if roundTrip, err := parseDuration(formatDuration(got)); err != nil || roundTrip != got {
+					t.Errorf("Expected %s to be formatted as %s which parses the same but got %s, %v", got, formatDuration(got), roundTrip, err)
+				}
+			}
+		})
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	var schema struct {
+		Defs map[string]struct {
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"$defs"`
+	}
+	if err := json.Unmarshal([]byte(JSONSchema), &schema); err != nil {
+		t.Fatal(err)
+	}
+
+	// Every field which is written needs to be in the schema
+	testCases := []struct {
+		def string
+		v   any
+	}{
+		{def: "calendar", v: jsonCalendar[struct{}]{}},
+		{def: "event", v: jsonEvent[struct{}]{}},
+		{def: "rule", v: jsonRule[struct{}]{}},
+		{def: "anchor", v: jsonAnchor{}},
+		{def: "metadata", v: Metadata{}},
+		{def: "workingHours", v: jsonWorkingHours{}},
+		{def: "dayWindow", v: jsonDayWindow{}},
+		{def: "track", v: Track{}},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.def, func(t *testing.T) {
+			properties := schema.Defs[tC.def].Properties
+			fields := reflect.TypeOf(tC.v)
+			for i := range fields.NumField() {
+				if fields.Field(i).Anonymous {
+					continue
+				}
+				name, _, _ := strings.Cut(fields.Field(i).Tag.Get("json"), ",")
+				if _, ok := properties[name]; !ok {
+					t.Errorf("Expected %s to have the property %q", tC.def, name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package ephemeris
+
+import "slices"
+
+// PlacementOf the position of an Event when overlapping Events are displayed
+// side by side, such as in a week view. Events which overlap, directly or
+// through other Events, form a cluster which is divided into Columns of equal
+// width.
+type PlacementOf[T any] struct {
+	Event EventOf[T]
+	// Column the index of the first column the Event is displayed in.
+	Column int
+	// Span the number of columns the Event is displayed across, which is
+	// more than 1 when the columns next to it are free during the Event.
+	Span int
+	// Columns the number of columns in the cluster of the Event.
+	Columns int
+}
+
+// Placement a PlacementOf Events without a Payload.
+type Placement = PlacementOf[struct{}]
+
+// Layout places each of the Events, such as the ones from Calendar.Expand,
+// into columns so that Events which overlap are displayed side by side. The
+// Placements are ordered by the Start of the Events, with longer Events first
+// when they start at the same time.
+//
+// Each Event is placed in the leftmost column which is free when it starts and
+// then spans to the right across the columns which are free for its whole
+// duration. Instants are placed like Events which end when they start.
+func Layout[T any](events []EventOf[T]) []PlacementOf[T] {
+	sorted := slices.Clone(events)
+	slices.SortStableFunc(sorted, func(a, b EventOf[T]) int {
+		if order := a.Start.Compare(b.Start); order != 0 {
+			return order
+		}
+		return b.effectiveEnd().Compare(a.effectiveEnd())
+	})
+
+	var placements []PlacementOf[T]
+	for start := 0; start < len(sorted); {
+		// A cluster continues while the next Event overlaps an Event of the
+		// cluster
+		end := start + 1
+		for end < len(sorted) && slices.ContainsFunc(sorted[start:end], func(other EventOf[T]) bool {
+			return isLayoutOverlap(other, sorted[end])
+		}) {
+			end++
+		}
+
+		placements = append(placements, layoutCluster(sorted[start:end])...)
+		start = end
+	}
+
+	return placements
+}
+
+// layoutCluster places the Events of a cluster, which are ordered by their
+// Start, into columns.
+func layoutCluster[T any](events []EventOf[T]) []PlacementOf[T] {
+	var columns [][]EventOf[T]
+	placements := make([]PlacementOf[T], len(events))
+	for i, event := range events {
+		column := slices.IndexFunc(columns, func(column []EventOf[T]) bool {
+			return !slices.ContainsFunc(column, func(other EventOf[T]) bool {
+				return isLayoutOverlap(other, event)
+			})
+		})
+		if column < 0 {
+			column = len(columns)
+			columns = append(columns, nil)
+		}
+
+		columns[column] = append(columns[column], event)
+		placements[i] = PlacementOf[T]{Event: event, Column: column}
+	}
+
+	for i := range placements {
+		placement := &placements[i]
+		placement.Columns = len(columns)
+		placement.Span = 1
+		for next := placement.Column + 1; next < len(columns); next++ {
+			if slices.ContainsFunc(columns[next], func(other EventOf[T]) bool {
+				return isLayoutOverlap(placement.Event, other)
+			}) {
+				break
+			}
+			placement.Span++
+		}
+	}
+
+	return placements
+}
+
+// isLayoutOverlap determines if the Events would be displayed at the same time,
+// where instants are displayed at their Start.
+func isLayoutOverlap[T any](e1, e2 EventOf[T]) bool {
+	return e1.Start.Before(e2.effectiveEnd()) && e2.Start.Before(e1.effectiveEnd()) ||
+		e1.Start.Equal(e2.Start)
+}
@@ -0,0 +1,58 @@
+package ephemeris
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLayout(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, time.March, 4, hour, minute, 0, 0, time.UTC)
+	}
+
+	calendar := Calendar{
+		Entries: []Rule{
+			{Event: Event{Start: at(11, 0), End: at(12, 0), Name: "D"}},
+			{Event: Event{Start: at(9, 0), End: at(11, 0), Name: "A"}},
+			{Event: Event{Start: at(9, 30), End: at(10, 30), Name: "B"}},
+			{Event: Event{Start: at(10, 0), End: at(12, 0), Name: "C"}},
+			{Event: Event{Start: at(13, 0), End: at(14, 0), Name: "E"}},
+			{Event: Event{Start: at(13, 30), End: at(13, 30), Name: "F"}},
+			// Instants starting at the same time are displayed side by side
+			{Event: Event{Start: at(15, 0), End: at(15, 0), Name: "G"}},
+			{Event: Event{Start: at(15, 0), End: at(15, 0), Name: "H"}},
+		},
+	}
+
+	events, err := calendar.Expand(at(0, 0), at(24, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		name    string
+		column  int
+		span    int
+		columns int
+	}{
+		{name: "A", column: 0, span: 1, columns: 3},
+		{name: "B", column: 1, span: 1, columns: 3},
+		{name: "C", column: 2, span: 1, columns: 3},
+		{name: "D", column: 0, span: 2, columns: 3},
+		{name: "E", column: 0, span: 1, columns: 2},
+		{name: "F", column: 1, span: 1, columns: 2},
+		{name: "G", column: 0, span: 1, columns: 2},
+		{name: "H", column: 1, span: 1, columns: 2},
+	}
+
+	got := Layout(events)
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %d placements but got %v", len(expected), got)
+	}
+	for i, e := range expected {
+		p := got[i]
+		if p.Event.Name != e.name || p.Column != e.column || p.Span != e.span || p.Columns != e.columns {
+			t.Errorf("Expected %s in column %d spanning %d of %d but got %s in column %d spanning %d of %d", e.name, e.column, e.span, e.columns, p.Event.Name, p.Column, p.Span, p.Columns)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package ephemeris
+
+import "reflect"
+
+// MergeBy determines which Events are the same when merging the Events of a
+// Calendar's View.
+//
+// Condensing can leave an Event as touching pieces, for example when it is
+// split by another Event with the same Name, and repeating Rules with back to
+// back occurrences result in an Event per occurrence. Merging combines Events
+// which touch or overlap and have the same identity into a single Event.
+type MergeBy int
+
+const (
+	// MergeNone Events are not merged.
+	MergeNone MergeBy = iota
+	// MergeByName Events with the same Name are merged.
+	MergeByName
+	// MergeByRuleID Events expanded from the same Rule are merged. Events
+	// from Rules without an ID are not merged.
+	MergeByRuleID
+	// MergeByPayload Events with equal Payloads, as determined by
+	// reflect.DeepEqual, are merged.
+	MergeByPayload
+)
+
+// mergeEvents combines the Events, which are ordered by their Start, that
+// touch or overlap and have the same identity. The merged Event keeps the
+// fields of the earliest Event, other than the End, and is Truncated or Split
+// when any of the Events it was merged from were. Instants are never merged.
+func mergeEvents[T any](events []EventOf[T], mergeBy MergeBy) []EventOf[T] {
+	var mergedEvents []EventOf[T]
+	// last the index of the latest Event in mergedEvents which is not an
+	// instant, instants between touching Events do not prevent merging.
+	last := -1
+	for _, event := range events {
+		if event.IsInstant() {
+			mergedEvents = append(mergedEvents, event)
+			continue
+		}
+
+		if last >= 0 && isMergeable(mergedEvents[last], event, mergeBy) {
+			merged := &mergedEvents[last]
+			if event.effectiveEnd().After(merged.effectiveEnd()) {
+				merged.End = event.End
+			}
+			merged.Truncated = merged.Truncated || event.Truncated
+			merged.Split = merged.Split || event.Split
+			continue
+		}
+
+		mergedEvents = append(mergedEvents, event)
+		last = len(mergedEvents) - 1
+	}
+
+	return mergedEvents
+}
+
+// isMergeable determines if e2, which does not start before e1, touches or
+// overlaps e1 and has the same identity.
+func isMergeable[T any](e1, e2 EventOf[T], mergeBy MergeBy) bool {
+	if e2.Start.After(e1.effectiveEnd()) {
+		return false
+	}
+
+	switch mergeBy {
+	case MergeByName:
+		return e1.Name == e2.Name
+	case MergeByRuleID:
+		return e1.RuleID != "" && e1.RuleID == e2.RuleID
+	case MergeByPayload:
+		return reflect.DeepEqual(e1.Payload, e2.Payload)
+	}
+
+	return false
+}
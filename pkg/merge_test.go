@@ -0,0 +1,114 @@
+package ephemeris
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestMergeView(t *testing.T) {
+	at := func(hour int) time.Time {
+		return time.Date(2024, time.March, 4, hour, 0, 0, 0, time.UTC)
+	}
+
+	entries := []RuleOf[string]{
+		{ID: "work", Event: Event{Start: at(9), End: at(12), Name: "Work"}, Payload: "desk"},
+		{ID: "work-late", Event: Event{Start: at(12), End: at(14), Name: "Work"}, Payload: "phone"},
+		{
+			ID:                  "on-call",
+			Event:               Event{Start: at(14), End: at(15), Name: "On Call"},
+			Payload:             "phone",
+			RepeatHourly:        1,
+			RepeatForwardUntil:  at(16),
+			RepeatBackwardUntil: at(14),
+		},
+		{ID: "deploy", Event: Event{Start: at(15), End: at(15), Name: "Deploy"}},
+	}
+
+	testCases := []struct {
+		desc     string
+		mergeBy  MergeBy
+		expected []EventOf[string]
+	}{
+		{
+			desc:    "No Merging",
+			mergeBy: MergeNone,
+			expected: []EventOf[string]{
+				{Start: at(9), End: at(12), Name: "Work", Payload: "desk", RecurrenceID: at(9), RuleID: "work"},
+				{Start: at(12), End: at(14), Name: "Work", Payload: "phone", RecurrenceID: at(12), RuleID: "work-late"},
+				{Start: at(14), End: at(15), Name: "On Call", Payload: "phone", RecurrenceID: at(14), RuleID: "on-call"},
+				{Start: at(15), End: at(16), Name: "On Call", Payload: "phone", RecurrenceID: at(15), RuleID: "on-call"},
+				{Start: at(15), End: at(15), Name: "Deploy", RecurrenceID: at(15), RuleID: "deploy"},
+				{Start: at(16), End: at(17), Name: "On Call", Payload: "phone", RecurrenceID: at(16), RuleID: "on-call"},
+			},
+		},
+		{
+			desc:    "By Name",
+			mergeBy: MergeByName,
+			expected: []EventOf[string]{
+				{Start: at(9), End: at(14), Name: "Work", Payload: "desk", RecurrenceID: at(9), RuleID: "work"},
+				{Start: at(14), End: at(17), Name: "On Call", Payload: "phone", RecurrenceID: at(14), RuleID: "on-call"},
+				{Start: at(15), End: at(15), Name: "Deploy", RecurrenceID: at(15), RuleID: "deploy"},
+			},
+		},
+		{
+			desc:    "By Rule ID",
+			mergeBy: MergeByRuleID,
+			expected: []EventOf[string]{
+				{Start: at(9), End: at(12), Name: "Work", Payload: "desk", RecurrenceID: at(9), RuleID: "work"},
+				{Start: at(12), End: at(14), Name: "Work", Payload: "phone", RecurrenceID: at(12), RuleID: "work-late"},
+				{Start: at(14), End: at(17), Name: "On Call", Payload: "phone", RecurrenceID: at(14), RuleID: "on-call"},
+				{Start: at(15), End: at(15), Name: "Deploy", RecurrenceID: at(15), RuleID: "deploy"},
+			},
+		},
+		{
+			desc:    "By Payload",
+			mergeBy: MergeByPayload,
+			expected: []EventOf[string]{
+				{Start: at(9), End: at(12), Name: "Work", Payload: "desk", RecurrenceID: at(9), RuleID: "work"},
+				{Start: at(12), End: at(17), Name: "Work", Payload: "phone", RecurrenceID: at(12), RuleID: "work-late"},
+				{Start: at(15), End: at(15), Name: "Deploy", RecurrenceID: at(15), RuleID: "deploy"},
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			calendar := CalendarOf[string]{Entries: entries, Merge: tC.mergeBy}
+
+			got, err := calendar.View(at(0), at(24))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !slices.Equal(tC.expected, got) {
+				t.Errorf("Expected %v but got %v", tC.expected, got)
+			}
+		})
+	}
+}
+
+func TestMergeSplitEvent(t *testing.T) {
+	at := func(hour int) time.Time {
+		return time.Date(2024, time.March, 4, hour, 0, 0, 0, time.UTC)
+	}
+
+	calendar := Calendar{
+		Entries: []Rule{
+			{Event: Event{Start: at(9), End: at(17), Name: "Focus"}},
+			{Event: Event{Start: at(12), End: at(13), Name: "Focus"}},
+		},
+		Merge: MergeByName,
+	}
+
+	got, err := calendar.View(at(0), at(24))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Event{
+		{Start: at(9), End: at(17), Name: "Focus", RecurrenceID: at(9), Truncated: true, Split: true},
+	}
+	if !slices.Equal(expected, got) {
+		t.Errorf("Expected %v but got %v", expected, got)
+	}
+}
@@ -0,0 +1,28 @@
+package ephemeris
+
+import "slices"
+
+// Overlay combines the Calendar with layers of other Calendars, such as team
+// overrides and personal exceptions on top of a base schedule, into a single
+// Calendar. Later layers take precedence over earlier ones and the Calendar
+// itself is the lowest layer. Within a layer the order of the Rules is kept,
+// so condensing the combined Calendar favors higher layers and then later
+// Rules.
+//
+// The Layer of each Rule is set to the Name of the Calendar it came from so
+// every Event of a View reports the layer which supplied it. Rules may be
+// anchored to Rules of other layers, so Rule IDs should be unique across all
+// of the layers. The other settings, such as Merge and WorkingHours, are taken
+// from the Calendar.
+func (c CalendarOf[T]) Overlay(layers ...CalendarOf[T]) CalendarOf[T] {
+	combined := c
+	combined.Entries = nil
+	for _, layer := range slices.Concat([]CalendarOf[T]{c}, layers) {
+		for _, rule := range layer.Entries {
+			rule.Layer = layer.Name
+			combined.Entries = append(combined.Entries, rule)
+		}
+	}
+
+	return combined
+}
@@ -0,0 +1,58 @@
+package ephemeris
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestOverlay(t *testing.T) {
+	at := func(day, hour int) time.Time {
+		return time.Date(2024, time.March, day, hour, 0, 0, 0, time.UTC)
+	}
+
+	base := Calendar{
+		Name: "base",
+		Entries: []Rule{
+			{ID: "shift", Event: Event{Start: at(4, 9), End: at(4, 17), Name: "Shift"}, RepeatDaily: 1, RepeatBackwardUntil: at(4, 9)},
+		},
+		Merge: MergeByName,
+	}
+	team := Calendar{
+		Name: "team",
+		Entries: []Rule{
+			{ID: "training", Event: Event{Start: at(5, 9), End: at(5, 12), Name: "Training"}},
+			{ID: "offsite", Event: Event{Start: at(6, 0), End: at(7, 0), Name: "Offsite"}},
+		},
+	}
+	personal := Calendar{
+		Name: "personal",
+		Entries: []Rule{
+			{ID: "dentist", Event: Event{Start: at(5, 11), End: at(5, 13), Name: "Dentist"}},
+			// Anchored to a Rule of a lower layer
+			{ID: "prep", Event: Event{Name: "Prep"}, Anchor: &Anchor{RuleID: "offsite", Offset: -time.Hour, Duration: time.Hour}},
+		},
+	}
+
+	calendar := base.Overlay(team, personal)
+	got, err := calendar.View(at(4, 0), at(7, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Event{
+		{Start: at(4, 9), End: at(4, 17), Name: "Shift", RecurrenceID: at(4, 9), RuleID: "shift", Layer: "base"},
+		{Start: at(5, 9), End: at(5, 11), Name: "Training", RecurrenceID: at(5, 9), RuleID: "training", Truncated: true, Layer: "team"},
+		{Start: at(5, 11), End: at(5, 13), Name: "Dentist", RecurrenceID: at(5, 11), RuleID: "dentist", Layer: "personal"},
+		{Start: at(5, 13), End: at(5, 17), Name: "Shift", RecurrenceID: at(5, 9), RuleID: "shift", Truncated: true, Layer: "base"},
+		{Start: at(5, 23), End: at(6, 0), Name: "Prep", RecurrenceID: at(5, 23), RuleID: "prep", Layer: "personal"},
+		{Start: at(6, 0), End: at(7, 0), Name: "Offsite", RecurrenceID: at(6, 0), RuleID: "offsite", Layer: "team"},
+	}
+	if !slices.Equal(expected, got) {
+		t.Errorf("Expected %v but got %v", expected, got)
+	}
+
+	if len(base.Entries) != 1 || base.Entries[0].Layer != "" {
+		t.Errorf("Expected the layers to not be changed but got %v", base.Entries)
+	}
+}
@@ -0,0 +1,233 @@
+package ephemeris
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// calendarSchema JSONSchema which calendar definition files are checked
+// against.
+var calendarSchema = mustParseSchema(JSONSchema)
+
+// schema the keywords of JSON Schema which are used by JSONSchema. Formats are
+// checked with the same parsers as the JSON representation so a file which is
+// accepted can be read.
+type schema struct {
+	Ref                   string             `json:"$ref"`
+	Defs                  map[string]*schema `json:"$defs"`
+	Type                  string             `json:"type"`
+	Format                string             `json:"format"`
+	Enum                  []json.RawMessage  `json:"enum"`
+	Const                 json.RawMessage    `json:"const"`
+	Minimum               *float64           `json:"minimum"`
+	Maximum               *float64           `json:"maximum"`
+	Properties            map[string]*schema `json:"properties"`
+	Required              []string           `json:"required"`
+	AdditionalProperties  *schema            `json:"additionalProperties"`
+	UnevaluatedProperties *schema            `json:"unevaluatedProperties"`
+	PropertyNames         *schema            `json:"propertyNames"`
+	Items                 *schema            `json:"items"`
+
+	// never whether the schema is false, which no value matches.
+	never bool
+	// root the schema $ref is resolved against.
+	root *schema
+}
+
+func mustParseSchema(data string) *schema {
+	var s schema
+	if err := json.Unmarshal([]byte(data), &s); err != nil {
+		panic(fmt.Sprintf("invalid schema: %v", err))
+	}
+	s.setRoot(&s)
+	return &s
+}
+
+// UnmarshalJSON reads a schema, which can also be true or false.
+func (s *schema) UnmarshalJSON(data []byte) error {
+	switch string(bytes.TrimSpace(data)) {
+	case "true":
+		return nil
+	case "false":
+		s.never = true
+		return nil
+	}
+
+	type plain schema
+	return json.Unmarshal(data, (*plain)(s))
+}
+
+func (s *schema) setRoot(root *schema) {
+	if s == nil {
+		return
+	}
+
+	s.root = root
+	for _, child := range s.Defs {
+		child.setRoot(root)
+	}
+	for _, child := range s.Properties {
+		child.setRoot(root)
+	}
+	for _, child := range []*schema{s.AdditionalProperties, s.UnevaluatedProperties, s.PropertyNames, s.Items} {
+		child.setRoot(root)
+	}
+}
+
+// ref the schema referenced by $ref, which are all definitions of the root.
+func (s *schema) ref() *schema {
+	if s.Ref == "" {
+		return nil
+	}
+	return s.root.Defs[strings.TrimPrefix(s.Ref, "#/$defs/")]
+}
+
+// evaluates whether the key is one of the properties of the schema or of the
+// schemas it references.
+func (s *schema) evaluates(key string) bool {
+	for ; s != nil; s = s.ref() {
+		if _, ok := s.Properties[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// check returns an error for the first part of the node which does not match
+// the schema.
+func (s *schema) check(n *node, path string) error {
+	if s.never {
+		return lineError(n.line, path, errors.New("not allowed"))
+	}
+	if ref := s.ref(); ref != nil {
+		if err := ref.check(n, path); err != nil {
+			return err
+		}
+	}
+
+	if err := s.checkValue(n); err != nil {
+		return lineError(n.line, path, err)
+	}
+
+	if s.Items != nil {
+		for i, item := range n.items {
+			if err := s.Items.check(item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if n.kind != nodeObject {
+		return nil
+	}
+	for _, key := range s.Required {
+		if n.get(key) == nil {
+			return lineError(n.line, path, fmt.Errorf("missing %s", key))
+		}
+	}
+	for _, f := range n.fields {
+		fieldPath := fieldPath(path, f.key)
+		if s.PropertyNames != nil {
+			if err := s.PropertyNames.check(&node{kind: nodeString, line: f.line, value: f.key}, fieldPath); err != nil {
+				return err
+			}
+		}
+
+		property, ok := s.Properties[f.key]
+		switch {
+		case ok:
+		case s.AdditionalProperties != nil:
+			property = s.AdditionalProperties
+		case s.UnevaluatedProperties != nil && !s.evaluates(f.key):
+			property = s.UnevaluatedProperties
+		default:
+			continue
+		}
+
+		if property.never {
+			return lineError(f.line, fieldPath, ErrUnknownField)
+		}
+		if err := property.check(f.value, fieldPath); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkValue checks the keywords which apply to the node itself.
+func (s *schema) checkValue(n *node) error {
+	switch s.Type {
+	case "":
+	case "integer":
+		if !n.isInteger() {
+			return fmt.Errorf("expected an integer but got %s", n.describe())
+		}
+	default:
+		if nodeKindNames[n.kind] != s.Type {
+			return fmt.Errorf("expected %s but got %s", withArticle(s.Type), n.describe())
+		}
+	}
+
+	value := n.appendJSON(nil)
+	if s.Const != nil && !bytes.Equal(value, s.Const) {
+		return fmt.Errorf("expected %s but got %s", s.Const, value)
+	}
+	if s.Enum != nil && !slices.ContainsFunc(s.Enum, func(e json.RawMessage) bool { return bytes.Equal(value, e) }) {
+		names := make([]string, len(s.Enum))
+		for i, e := range s.Enum {
+			names[i] = string(e)
+		}
+		return fmt.Errorf("expected one of %s but got %s", strings.Join(names, ", "), value)
+	}
+
+	if n.kind == nodeNumber {
+		number, err := strconv.ParseFloat(n.value, 64)
+		if err != nil {
+			return err
+		}
+		if s.Minimum != nil && number < *s.Minimum {
+			return fmt.Errorf("expected at least %v but got %s", *s.Minimum, n.value)
+		}
+		if s.Maximum != nil && number > *s.Maximum {
+			return fmt.Errorf("expected at most %v but got %s", *s.Maximum, n.value)
+		}
+	}
+
+	if n.kind == nodeString {
+		switch s.Format {
+		case "date-time":
+			if _, err := parseTime(n.value, nil); err != nil {
+				return fmt.Errorf("invalid time %q, expected an RFC 3339 timestamp", n.value)
+			}
+		case "duration":
+			if _, err := parseDuration(n.value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// describe the node for errors.
+func (n *node) describe() string {
+	switch n.kind {
+	case nodeArray, nodeObject:
+		return withArticle(nodeKindNames[n.kind])
+	default:
+		return string(n.appendJSON(nil))
+	}
+}
+
+func withArticle(noun string) string {
+	if strings.ContainsRune("aeiou", rune(noun[0])) {
+		return "an " + noun
+	}
+	return "a " + noun
+}
@@ -0,0 +1,106 @@
+package ephemeris
+
+import (
+	"slices"
+	"time"
+)
+
+// SlotOptions constraints used by FindMeetingSlots. The zero value has no
+// buffers or preferred times.
+type SlotOptions struct {
+	// BufferBefore the time which needs to be free before each Event, for
+	// example to travel to it.
+	BufferBefore time.Duration
+	// BufferAfter the time which needs to be free after each Event.
+	BufferAfter time.Duration
+
+	// Preferred the times which are preferred for the meeting. Slots which
+	// are completely within them are ranked before other slots. A nil
+	// Preferred does not prefer any time.
+	Preferred *WorkingHours
+
+	// Step how often candidate slots start, aligned to the clock in the
+	// location of the start of the timeframe, for example every 15 minutes
+	// starts at :00, :15, :30 and :45 even in locations with an offset of a
+	// half hour. A zero Step uses 15 minutes.
+	Step time.Duration
+
+	// Limit the maximum number of slots to return, 0 returns all of them.
+	Limit int
+}
+
+// Slot a candidate time for a meeting.
+type Slot struct {
+	Interval
+	// Preferred the slot is within the preferred times.
+	Preferred bool
+}
+
+// FindMeetingSlots finds the times of length duration within the timeframe of
+// viewStart(inclusive) and viewEnd(exclusive) where every Calendar is free, as
+// determined by FreeSlots, so each Calendar's WorkingHours, in its own
+// location, is used.
+//
+// The slots are ranked with preferred slots first and then by their Start.
+func FindMeetingSlots[T any](calendars []CalendarOf[T], duration time.Duration, viewStart, viewEnd time.Time, options SlotOptions) ([]Slot, error) {
+	free := []Interval{{Start: viewStart, End: viewEnd}}
+	for i := range calendars {
+		calendarFree, err := calendars[i].free(viewStart, viewEnd, options.BufferBefore, options.BufferAfter)
+		if err != nil {
+			return nil, err
+		}
+		free = intersectIntervals(free, calendarFree)
+	}
+
+	var preferred []Interval
+	if options.Preferred != nil {
+		preferred = options.Preferred.intervals(viewStart, viewEnd)
+	}
+
+	step := options.Step
+	if step <= 0 {
+		step = 15 * time.Minute
+	}
+
+	var slots []Slot
+	for _, interval := range free {
+		start := alignToClock(interval.Start, step, viewStart.Location())
+		for ; !start.Add(duration).After(interval.End); start = alignToClock(start.Add(step), step, viewStart.Location()) {
+			slot := Slot{Interval: Interval{Start: start, End: start.Add(duration)}}
+			slot.Preferred = slices.ContainsFunc(preferred, func(p Interval) bool {
+				return !slot.Start.Before(p.Start) && !slot.End.After(p.End)
+			})
+			slots = append(slots, slot)
+		}
+	}
+
+	slices.SortStableFunc(slots, func(a, b Slot) int {
+		if a.Preferred != b.Preferred {
+			if a.Preferred {
+				return -1
+			}
+			return 1
+		}
+		return a.Start.Compare(b.Start)
+	})
+
+	if options.Limit > 0 && len(slots) > options.Limit {
+		slots = slots[:options.Limit]
+	}
+
+	return slots, nil
+}
+
+// alignToClock finds the first time at or after t which is a multiple of step
+// on the clock of the location, so offsets which are not whole hours and
+// daylight saving time changes keep the slots aligned.
+func alignToClock(t time.Time, step time.Duration, loc *time.Location) time.Time {
+	_, offset := t.In(loc).Zone()
+	shift := time.Duration(offset) * time.Second
+
+	aligned := t.Add(shift).Truncate(step).Add(-shift)
+	if aligned.Before(t) {
+		aligned = aligned.Add(step)
+	}
+	return aligned
+}
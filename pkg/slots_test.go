@@ -0,0 +1,96 @@
+package ephemeris
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestFindMeetingSlots(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, time.March, 4, hour, minute, 0, 0, time.UTC)
+	}
+	slot := func(hour, minute int, preferred bool) Slot {
+		return Slot{Interval: Interval{Start: at(hour, minute), End: at(hour, minute).Add(30 * time.Minute)}, Preferred: preferred}
+	}
+
+	aliceHours := WeekdayHours(9*time.Hour, 17*time.Hour, time.UTC)
+	bobHours := WeekdayHours(9*time.Hour, 17*time.Hour, newYork)
+	calendars := []Calendar{
+		{
+			Name:         "Alice",
+			Entries:      []Rule{{Event: Event{Start: at(14, 0), End: at(15, 0), Name: "Interview"}}},
+			WorkingHours: &aliceHours,
+		},
+		{
+			Name:         "Bob",
+			Entries:      []Rule{{Event: Event{Start: at(16, 0), End: at(16, 30), Name: "Call"}}},
+			WorkingHours: &bobHours,
+		},
+	}
+	lateAfternoon := WeekdayHours(16*time.Hour, 17*time.Hour, time.UTC)
+
+	testCases := []struct {
+		desc     string
+		options  SlotOptions
+		expected []Slot
+	}{
+		{
+			desc:     "Common Free Time",
+			options:  SlotOptions{Step: 30 * time.Minute},
+			expected: []Slot{slot(15, 0, false), slot(15, 30, false), slot(16, 30, false)},
+		},
+		{
+			desc:     "Buffers",
+			options:  SlotOptions{BufferBefore: 15 * time.Minute, BufferAfter: 15 * time.Minute},
+			expected: []Slot{slot(15, 15, false)},
+		},
+		{
+			desc:     "Preferred Times First",
+			options:  SlotOptions{Step: 30 * time.Minute, Preferred: &lateAfternoon},
+			expected: []Slot{slot(16, 30, true), slot(15, 0, false), slot(15, 30, false)},
+		},
+		{
+			desc:     "Limit",
+			options:  SlotOptions{Step: 30 * time.Minute, Limit: 1},
+			expected: []Slot{slot(15, 0, false)},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			got, err := FindMeetingSlots(calendars, 30*time.Minute, at(0, 0), at(24, 0), tC.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !slices.Equal(tC.expected, got) {
+				t.Errorf("Expected %v but got %v", tC.expected, got)
+			}
+		})
+	}
+}
+
+func TestFindMeetingSlotsAlignedToClock(t *testing.T) {
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, time.March, 4, hour, minute, 0, 0, kolkata)
+	}
+
+	// Kolkata is 5 and a half hours ahead of UTC so whole hours on its clock
+	// are half hours in UTC
+	got, err := FindMeetingSlots([]Calendar{{Name: "Priya"}}, time.Hour, at(9, 10), at(12, 0), SlotOptions{Step: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Slot{
+		{Interval: Interval{Start: at(10, 0), End: at(11, 0)}},
+		{Interval: Interval{Start: at(11, 0), End: at(12, 0)}},
+	}
+	if !slices.Equal(expected, got) {
+		t.Errorf("Expected %v but got %v", expected, got)
+	}
+}
@@ -0,0 +1,286 @@
+package ephemeris
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// SplitAt splits a repeating Rule into two Rules at the first occurrence which
+// starts at or after t, which is used to edit "this and following" occurrences
+// of a series without changing earlier ones.
+//
+// The before Rule is the original with its RepeatForwardUntil ending just
+// before the chosen occurrence and the after Rule repeats the same way
+// starting at the chosen occurrence. Skip, Canceled and Overrides are divided
+// so each Rule only keeps the ones which belong to its occurrences. Rules using
+// Cron or business days are split at their generated occurrences the same way.
+//
+// When the Rule has an ID the after Rule gets a new ID derived from its
+// contents like Calendar.AssignIDs, so the halves can be told apart.
+//
+// When there is no occurrence to split at, for example the Rule does not repeat
+// or stops repeating before t, the original Rule is returned as before and
+// after is the zero value. When there are no occurrences before the split the
+// zero value is returned as before.
+func (r RuleOf[T]) SplitAt(t time.Time) (before, after RuleOf[T]) {
+	before, after, hasBefore, hasAfter := r.splitAt(t)
+	if !hasBefore {
+		before = RuleOf[T]{}
+	}
+	if !hasAfter {
+		return r, RuleOf[T]{}
+	}
+
+	return before, after
+}
+
+// splitAt does the work of SplitAt while reporting if each half has any
+// occurrences.
+func (r RuleOf[T]) splitAt(t time.Time) (before, after RuleOf[T], hasBefore, hasAfter bool) {
+	before = r
+	hasBefore = true
+
+	var splitStart, splitEnd time.Time
+	if r.isGenerated() {
+		next, ok := r.nextOccurrenceFrom(t)
+		if !ok {
+			return r, RuleOf[T]{}, true, false
+		}
+		splitStart, splitEnd = next.Start, next.End
+
+		// The occurrences do not follow from the Start so any occurrence
+		// before the split can be used as the Start of the earlier half.
+		var previous EventOf[T]
+		previous, hasBefore = r.previousOccurrenceBefore(splitStart)
+		if hasBefore && !splitStart.After(r.Start) {
+			before.Start, before.End = previous.Start, previous.End
+		}
+	} else {
+		n, ok := r.firstOccurrenceFrom(t)
+		if !ok {
+			return r, RuleOf[T]{}, true, false
+		}
+		splitStart, splitEnd = r.repeat(n)
+
+		if n <= 0 {
+			// The original Event belongs to the later half so the earlier
+			// half needs to start from the occurrence just before the split.
+			before.Start, before.End = r.repeat(n - 1)
+			hasBefore = r.RepeatBackwardUntil.IsZero() || !before.Start.Before(r.RepeatBackwardUntil)
+		}
+	}
+
+	after = r
+	after.Start, after.End = splitStart, splitEnd
+	after.RepeatBackwardUntil = splitStart
+
+	before.RepeatForwardUntil = splitStart.Add(-time.Nanosecond)
+
+	isBefore := func(moment time.Time) bool { return moment.Before(splitStart) }
+	before.Skip, after.Skip = partition(r.Skip, isBefore)
+	before.Canceled, after.Canceled = partition(r.Canceled, isBefore)
+	before.Overrides, after.Overrides = partition(r.Overrides, func(override EventOf[T]) bool {
+		return override.RecurrenceID.Before(splitStart)
+	})
+
+	if r.ID != "" && hasBefore {
+		after.ID = ""
+		after.ID = after.contentID(0)
+	}
+
+	return before, after, hasBefore, true
+}
+
+// firstOccurrenceFrom finds n for the first occurrence of the Rule, including
+// skipped ones, which starts at or after t.
+func (r RuleOf[T]) firstOccurrenceFrom(t time.Time) (int, bool) {
+	period := r.period()
+	if period <= 0 {
+		// Splitting an Event which does not repeat has no effect
+		return 0, false
+	}
+
+	n := int(t.Sub(r.Start) / period)
+	for {
+		start, _ := r.repeat(n - 1)
+		if start.Before(t) {
+			break
+		}
+		n--
+	}
+	for {
+		start, _ := r.repeat(n)
+		if !start.Before(t) {
+			break
+		}
+		n++
+	}
+
+	start, _ := r.repeat(n)
+	for n < 0 && !r.RepeatBackwardUntil.IsZero() && start.Before(r.RepeatBackwardUntil) {
+		n++
+		start, _ = r.repeat(n)
+	}
+	if n > 0 && !r.RepeatForwardUntil.IsZero() && start.After(r.RepeatForwardUntil) {
+		return 0, false
+	}
+
+	return n, true
+}
+
+// occurrenceSearchYears how many years to look for an occurrence of a Rule
+// using Cron or business days before giving up. Every combination of a day of
+// the month and a day of the week repeats within 28 years.
+const occurrenceSearchYears = 28
+
+// isGenerated whether the occurrences of the Rule are generated from Cron or
+// business days rather than repeating the Start with a period.
+func (r RuleOf[T]) isGenerated() bool {
+	return r.Cron != "" || r.RepeatBusinessDaily > 0 || r.RepeatBusinessDayOfMonth != 0
+}
+
+// nextOccurrenceFrom finds the first generated occurrence of the Rule,
+// including skipped ones, which starts at or after t.
+func (r RuleOf[T]) nextOccurrenceFrom(t time.Time) (EventOf[T], bool) {
+	unskipped := r
+	unskipped.Skip = nil
+
+	for year := 0; year < occurrenceSearchYears; year++ {
+		viewStart := t.AddDate(year, 0, 0)
+		if !r.RepeatForwardUntil.IsZero() && viewStart.After(r.RepeatForwardUntil) && viewStart.After(r.Start) {
+			break
+		}
+
+		for _, occurrence := range unskipped.occurrences(viewStart, t.AddDate(year+1, 0, 0)) {
+			if !occurrence.Start.Before(t) {
+				return occurrence, true
+			}
+		}
+	}
+
+	return EventOf[T]{}, false
+}
+
+// previousOccurrenceBefore finds the last generated occurrence of the Rule,
+// including skipped ones, which starts before t.
+func (r RuleOf[T]) previousOccurrenceBefore(t time.Time) (EventOf[T], bool) {
+	unskipped := r
+	unskipped.Skip = nil
+
+	for year := 0; year < occurrenceSearchYears; year++ {
+		viewEnd := t.AddDate(-year, 0, 0)
+		if !r.RepeatBackwardUntil.IsZero() && viewEnd.Before(r.RepeatBackwardUntil) && viewEnd.Before(r.Start) {
+			break
+		}
+
+		occurrences := unskipped.occurrences(t.AddDate(-year-1, 0, 0), viewEnd)
+		for i := len(occurrences) - 1; i >= 0; i-- {
+			if occurrences[i].Start.Before(t) {
+				return occurrences[i], true
+			}
+		}
+	}
+
+	return EventOf[T]{}, false
+}
+
+// partition divides values into the ones which match and the ones which do not.
+func partition[T any](values []T, match func(T) bool) (matched, unmatched []T) {
+	for _, value := range values {
+		if match(value) {
+			matched = append(matched, value)
+		} else {
+			unmatched = append(unmatched, value)
+		}
+	}
+
+	return matched, unmatched
+}
+
+// SplitRule splits the Rule at the given index of Entries using Rule.SplitAt
+// and replaces it with the resulting Rules. Both are kept at the same position
+// so their priority relative to the rest of the Calendar does not change.
+//
+// The ID of the after Rule is made unique within the Calendar. Rules with an
+// Anchor to the split Rule keep deriving their occurrences from the before
+// Rule and get a copy, right after them, anchored to the after Rule, so no
+// derived occurrences are lost. The copies are split the same way as the Rule
+// itself, including Rules anchored to them in turn.
+func (c *CalendarOf[T]) SplitRule(index int, t time.Time) error {
+	if index < 0 || index >= len(c.Entries) {
+		return fmt.Errorf("rule index %d out of range for calendar with %d rules", index, len(c.Entries))
+	}
+
+	r := c.Entries[index]
+	before, after, hasBefore, hasAfter := r.splitAt(t)
+	if !hasAfter {
+		return fmt.Errorf("rule %d has no occurrence at or after %s to split at", index, t)
+	}
+
+	used := map[string]bool{}
+	for _, rule := range c.Entries {
+		used[rule.ID] = true
+	}
+	if after.ID != r.ID {
+		after.ID = uniqueID(after.ID, used)
+	}
+
+	var replacements []RuleOf[T]
+	if hasBefore {
+		replacements = append(replacements, before)
+	}
+	replacements = append(replacements, after)
+
+	c.Entries = slices.Replace(c.Entries, index, index+1, replacements...)
+
+	if after.ID != r.ID {
+		c.splitAnchored(r.ID, after.ID, after.Start, after.End.Sub(after.Start), used, map[string]bool{r.ID: true})
+	}
+
+	return nil
+}
+
+// splitAnchored adds a copy of every Rule anchored to id which is anchored to
+// newID instead, where splitStart and splitDuration are the occurrence the
+// anchor Rule was split at. Skip, Canceled and Overrides are divided at the
+// occurrence derived from the split one. Visited contains the IDs which have
+// already been split so Rules anchored to each other are only split once.
+func (c *CalendarOf[T]) splitAnchored(id, newID string, splitStart time.Time, splitDuration time.Duration, used, visited map[string]bool) {
+	for i := 0; i < len(c.Entries); i++ {
+		r := c.Entries[i]
+		if r.Anchor == nil || r.Anchor.RuleID != id {
+			continue
+		}
+
+		derivedStart := splitStart.Add(r.Anchor.Offset)
+		if r.Anchor.FromEnd {
+			derivedStart = derivedStart.Add(splitDuration)
+		}
+		isBefore := func(moment time.Time) bool { return moment.Before(derivedStart) }
+
+		before, after := r, r
+		anchor := *r.Anchor
+		anchor.RuleID = newID
+		after.Anchor = &anchor
+		before.Skip, after.Skip = partition(r.Skip, isBefore)
+		before.Canceled, after.Canceled = partition(r.Canceled, isBefore)
+		before.Overrides, after.Overrides = partition(r.Overrides, func(override EventOf[T]) bool {
+			return override.RecurrenceID.Before(derivedStart)
+		})
+		if r.ID != "" {
+			after.ID = ""
+			after.ID = uniqueID(after.contentID(i+1), used)
+		}
+
+		c.Entries[i] = before
+		c.Entries = slices.Insert(c.Entries, i+1, after)
+		// The copy is anchored to newID so it is not visited again
+		i++
+
+		if r.ID != "" && !visited[r.ID] {
+			visited[r.ID] = true
+			c.splitAnchored(r.ID, after.ID, derivedStart, r.Anchor.Duration, used, visited)
+		}
+	}
+}
@@ -0,0 +1,268 @@
+package ephemeris
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRuleSplitAt(t *testing.T) {
+	standup := Rule{
+		Event: Event{
+			Start: time.Date(2024, time.March, 4, 9, 0, 0, 0, time.UTC),
+			End:   time.Date(2024, time.March, 4, 10, 0, 0, 0, time.UTC),
+			Name:  "Standup",
+		},
+		RepeatWeekly:        1,
+		RepeatBackwardUntil: time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC),
+		RepeatForwardUntil:  time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC),
+		Skip:                []time.Time{time.Date(2024, time.March, 11, 9, 0, 0, 0, time.UTC)},
+		Canceled:            []time.Time{time.Date(2024, time.April, 1, 9, 0, 0, 0, time.UTC)},
+		Overrides: []Event{
+			{RecurrenceID: time.Date(2024, time.February, 26, 9, 0, 0, 0, time.UTC), Name: "Planning"},
+			{RecurrenceID: time.Date(2024, time.March, 25, 9, 0, 0, 0, time.UTC), Name: "Retro"},
+		},
+	}
+	viewStart := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	viewEnd := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		desc       string
+		splitAt    time.Time
+		splitStart time.Time
+	}{
+		{
+			desc:       "Split On Occurrence",
+			splitAt:    time.Date(2024, time.March, 18, 9, 0, 0, 0, time.UTC),
+			splitStart: time.Date(2024, time.March, 18, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			desc:       "Split Between Occurrences",
+			splitAt:    time.Date(2024, time.March, 14, 0, 0, 0, 0, time.UTC),
+			splitStart: time.Date(2024, time.March, 18, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			desc:       "Split Before Original Event",
+			splitAt:    time.Date(2024, time.February, 19, 9, 0, 0, 0, time.UTC),
+			splitStart: time.Date(2024, time.February, 19, 9, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			before, after := standup.SplitAt(tC.splitAt)
+
+			if !after.Start.Equal(tC.splitStart) {
+				t.Errorf("Expected after to start at %s but got %s", tC.splitStart, after.Start)
+			}
+
+			beforeEvents := before.Expand(viewStart, viewEnd)
+			afterEvents := after.Expand(viewStart, viewEnd)
+			for _, e := range beforeEvents {
+				if !e.Start.Before(tC.splitStart) {
+					t.Errorf("Expected before occurrences to start before the split but got %s", e.Start)
+				}
+			}
+			for _, e := range afterEvents {
+				if e.Start.Before(tC.splitStart) {
+					t.Errorf("Expected after occurrences to start at or after the split but got %s", e.Start)
+				}
+			}
+
+			// Together the halves make up the original Rule
+			expected := standup.Expand(viewStart, viewEnd)
+			got := append(beforeEvents, afterEvents...)
+			if !slices.Equal(expected, got) {
+				t.Errorf("Expected %v but got %v", expected, got)
+			}
+		})
+	}
+}
+
+func TestRuleSplitAtGeneratedOccurrences(t *testing.T) {
+	weekdays, err := ParseCron("0 9 * * MON-FRI", time.Hour, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	weekdays.Skip = []time.Time{time.Date(2024, time.March, 12, 9, 0, 0, 0, time.UTC)}
+
+	viewStart := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	viewEnd := time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		desc       string
+		rule       Rule
+		splitAt    time.Time
+		splitStart time.Time
+		hasBefore  bool
+	}{
+		{
+			desc:       "Cron",
+			rule:       weekdays,
+			splitAt:    time.Date(2024, time.March, 9, 0, 0, 0, 0, time.UTC),
+			splitStart: time.Date(2024, time.March, 11, 9, 0, 0, 0, time.UTC),
+			hasBefore:  true,
+		},
+		{
+			desc:       "Cron At Skipped Occurrence",
+			rule:       weekdays,
+			splitAt:    time.Date(2024, time.March, 12, 9, 0, 0, 0, time.UTC),
+			splitStart: time.Date(2024, time.March, 12, 9, 0, 0, 0, time.UTC),
+			hasBefore:  true,
+		},
+		{
+			desc: "Business Days Before Original Event",
+			rule: Rule{
+				Event: Event{
+					Start: time.Date(2024, time.March, 20, 9, 0, 0, 0, time.UTC),
+					End:   time.Date(2024, time.March, 20, 10, 0, 0, 0, time.UTC),
+					Name:  "Review",
+				},
+				RepeatBusinessDaily: 2,
+			},
+			splitAt:    time.Date(2024, time.March, 13, 12, 0, 0, 0, time.UTC),
+			splitStart: time.Date(2024, time.March, 14, 9, 0, 0, 0, time.UTC),
+			hasBefore:  true,
+		},
+		{
+			desc: "Business Day Of Month",
+			rule: Rule{
+				Event: Event{
+					Start: time.Date(2024, time.January, 2, 9, 0, 0, 0, time.UTC),
+					End:   time.Date(2024, time.January, 2, 10, 0, 0, 0, time.UTC),
+					Name:  "Payroll",
+				},
+				RepeatBusinessDayOfMonth: -1,
+				RepeatBackwardUntil:      time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+			},
+			splitAt:    time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+			splitStart: time.Date(2024, time.January, 31, 9, 0, 0, 0, time.UTC),
+			hasBefore:  false,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			before, after := tC.rule.SplitAt(tC.splitAt)
+
+			if !after.Start.Equal(tC.splitStart) {
+				t.Errorf("Expected after to start at %s but got %s", tC.splitStart, after.Start)
+			}
+			if hasBefore := !before.Start.IsZero(); hasBefore != tC.hasBefore {
+				t.Errorf("Expected before to exist to be %t but got %+v", tC.hasBefore, before)
+			}
+
+			var beforeEvents []Event
+			if tC.hasBefore {
+				beforeEvents = before.Expand(viewStart, viewEnd)
+			}
+			afterEvents := after.Expand(viewStart, viewEnd)
+			for _, e := range beforeEvents {
+				if !e.Start.Before(tC.splitStart) {
+					t.Errorf("Expected before occurrences to start before the split but got %s", e.Start)
+				}
+			}
+			for _, e := range afterEvents {
+				if e.Start.Before(tC.splitStart) {
+					t.Errorf("Expected after occurrences to start at or after the split but got %s", e.Start)
+				}
+			}
+
+			// Together the halves make up the original Rule
+			expected := tC.rule.Expand(viewStart, viewEnd)
+			got := append(beforeEvents, afterEvents...)
+			if !slices.Equal(expected, got) {
+				t.Errorf("Expected %v but got %v", expected, got)
+			}
+		})
+	}
+}
+
+func TestRuleSplitAtNoOccurrence(t *testing.T) {
+	rule := Rule{
+		Event: Event{
+			Start: time.Date(2024, time.March, 4, 9, 0, 0, 0, time.UTC),
+			End:   time.Date(2024, time.March, 4, 10, 0, 0, 0, time.UTC),
+			Name:  "Standup",
+		},
+		RepeatDaily:        1,
+		RepeatForwardUntil: time.Date(2024, time.March, 8, 9, 0, 0, 0, time.UTC),
+	}
+
+	before, after := rule.SplitAt(time.Date(2024, time.March, 9, 0, 0, 0, 0, time.UTC))
+	if !before.Start.Equal(rule.Start) || !before.RepeatForwardUntil.Equal(rule.RepeatForwardUntil) {
+		t.Errorf("Expected before to be the original rule but got %+v", before)
+	}
+	if !after.Start.IsZero() {
+		t.Errorf("Expected after to be empty but got %+v", after)
+	}
+}
+
+func TestCalendarSplitRule(t *testing.T) {
+	calendar := Calendar{
+		Entries: []Rule{
+			{Event: Event{Start: rightNow, End: rightNow.Add(8 * time.Hour), Name: "Working"}, RepeatDaily: 1},
+			{Event: Event{Start: rightNow.Add(time.Hour), End: rightNow.Add(2 * time.Hour), Name: "Standup"}, RepeatDaily: 1},
+			{Event: Event{Start: rightNow.Add(4 * time.Hour), End: rightNow.Add(5 * time.Hour), Name: "Lunch"}, RepeatDaily: 1},
+		},
+	}
+
+	splitAt := rightNow.AddDate(0, 0, 3).Add(time.Hour)
+	if err := calendar.SplitRule(1, splitAt); err != nil {
+		t.Fatal(err)
+	}
+
+	names := []string{}
+	for _, rule := range calendar.Entries {
+		names = append(names, rule.Name)
+	}
+	if expected := []string{"Working", "Standup", "Standup", "Lunch"}; !slices.Equal(expected, names) {
+		t.Errorf("Expected rules %v but got %v", expected, names)
+	}
+	if !calendar.Entries[2].Start.Equal(splitAt) {
+		t.Errorf("Expected the later half to start at %s but got %s", splitAt, calendar.Entries[2].Start)
+	}
+
+	if err := calendar.SplitRule(4, splitAt); err == nil {
+		t.Error("Expected an error for an index out of range")
+	}
+}
+
+func TestCalendarSplitRuleWithAnchor(t *testing.T) {
+	calendar := Calendar{
+		Entries: []Rule{
+			{ID: "board", Event: Event{Start: time.Date(2024, time.March, 4, 9, 0, 0, 0, time.UTC), End: time.Date(2024, time.March, 4, 10, 0, 0, 0, time.UTC), Name: "Board"}, RepeatWeekly: 1},
+			{ID: "prep", Event: Event{Name: "Prep"}, Anchor: &Anchor{RuleID: "board", Offset: -24 * time.Hour, Duration: time.Hour}},
+			{Event: Event{Name: "Reminder"}, Anchor: &Anchor{RuleID: "prep", Offset: -time.Hour, Duration: 5 * time.Minute}},
+		},
+	}
+	if err := calendar.SplitRule(0, time.Date(2024, time.March, 18, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+
+	ids := map[string]bool{}
+	for _, rule := range calendar.Entries {
+		if rule.ID != "" && ids[rule.ID] {
+			t.Errorf("Expected unique rule IDs but %q is used twice", rule.ID)
+		}
+		ids[rule.ID] = true
+	}
+
+	got, err := calendar.View(time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	starts := map[string][]int{}
+	for _, e := range got {
+		starts[e.Name] = append(starts[e.Name], e.Start.Day())
+	}
+
+	expected := map[string][]int{
+		"Board":    {4, 11, 18, 25},
+		"Prep":     {3, 10, 17, 24, 31},
+		"Reminder": {3, 10, 17, 24, 31},
+	}
+	for name, days := range expected {
+		if !slices.Equal(days, starts[name]) {
+			t.Errorf("Expected %s on the days %v but got %v", name, days, starts[name])
+		}
+	}
+}
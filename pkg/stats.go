@@ -0,0 +1,141 @@
+package ephemeris
+
+import "time"
+
+// Bucket the periods Stats are divided into.
+type Bucket int
+
+const (
+	// BucketNone Stats are only totaled over the whole timeframe.
+	BucketNone Bucket = iota
+	// BucketDay Stats are totaled for each day.
+	BucketDay
+	// BucketWeek Stats are totaled for each week, starting on Monday.
+	BucketWeek
+	// BucketMonth Stats are totaled for each month.
+	BucketMonth
+)
+
+// StatsOptions determines how Stats are totaled.
+type StatsOptions struct {
+	Bucket Bucket
+	// Location the days, weeks, and months of the Buckets are in, nil uses the
+	// location of the start of the timeframe. Days are measured by the clock
+	// so days where daylight saving time begins or ends are shorter or longer.
+	Location *time.Location
+}
+
+// Stats the time spent on the Events of a Calendar's View.
+type Stats struct {
+	// Total the time spent over the whole timeframe.
+	Total PeriodStats
+	// Buckets the time spent in each Bucket, ordered by their Start. The
+	// first and last Buckets only include the parts within the timeframe.
+	Buckets []PeriodStats
+}
+
+// PeriodStats the time spent on Events within a period of time.
+type PeriodStats struct {
+	Interval
+
+	// ByName the time spent on Events with each Name.
+	ByName map[string]time.Duration
+	// ByTag the time spent on Events with each tag of their Metadata. Events
+	// with several tags count towards each of them.
+	ByTag map[string]time.Duration
+	// ByRule the time spent on Events from each Rule, keyed by their RuleID.
+	// Events from all the Rules without an ID are totaled under "", use
+	// CalendarOf.AssignIDs to tell them apart.
+	ByRule map[string]time.Duration
+
+	// Busy the time covered by any Event.
+	Busy time.Duration
+	// Utilization the percentage of the period which is Busy.
+	Utilization float64
+}
+
+// Stats totals the time spent on the Events of the View for the timeframe of
+// viewStart(inclusive) and viewEnd(exclusive). Events which are only partially
+// within the timeframe, or a Bucket, only count the time within it and
+// instants do not count any time. The time the Idle Event would fill is not
+// counted.
+func (c *CalendarOf[T]) Stats(viewStart, viewEnd time.Time, options StatsOptions) (Stats, error) {
+	calendar := *c
+	calendar.Idle = nil
+
+	events, err := calendar.View(viewStart, viewEnd)
+	if err != nil {
+		return Stats{}, err
+	}
+
+	stats := Stats{Total: periodStats(events, Interval{Start: viewStart, End: viewEnd})}
+	for _, bucket := range buckets(viewStart, viewEnd, options) {
+		stats.Buckets = append(stats.Buckets, periodStats(events, bucket))
+	}
+
+	return stats, nil
+}
+
+// periodStats totals the time spent on the Events, which do not overlap, within
+// the period.
+func periodStats[T any](events []EventOf[T], period Interval) PeriodStats {
+	stats := PeriodStats{
+		Interval: period,
+		ByName:   map[string]time.Duration{},
+		ByTag:    map[string]time.Duration{},
+		ByRule:   map[string]time.Duration{},
+	}
+
+	for _, event := range events {
+		start, end := latest(event.Start, period.Start), earliest(event.effectiveEnd(), period.End)
+		if !start.Before(end) {
+			continue
+		}
+
+		duration := end.Sub(start)
+		stats.ByName[event.Name] += duration
+		stats.ByRule[event.RuleID] += duration
+		if event.Metadata != nil {
+			for _, tag := range event.Metadata.Tags {
+				stats.ByTag[tag] += duration
+			}
+		}
+		stats.Busy += duration
+	}
+
+	if period.Duration() > 0 {
+		stats.Utilization = 100 * float64(stats.Busy) / float64(period.Duration())
+	}
+
+	return stats
+}
+
+// buckets divides the timeframe into the Bucket periods of the options.
+func buckets(viewStart, viewEnd time.Time, options StatsOptions) []Interval {
+	if options.Bucket == BucketNone {
+		return nil
+	}
+
+	loc := options.Location
+	if loc == nil {
+		loc = viewStart.Location()
+	}
+
+	start := startOfDay(viewStart.In(loc))
+	next := func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }
+	switch options.Bucket {
+	case BucketWeek:
+		start = start.AddDate(0, 0, -(int(start.Weekday())+6)%7)
+		next = func(t time.Time) time.Time { return t.AddDate(0, 0, 7) }
+	case BucketMonth:
+		start = time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, loc)
+		next = func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }
+	}
+
+	var periods []Interval
+	for ; start.Before(viewEnd); start = next(start) {
+		periods = append(periods, Interval{Start: latest(start, viewStart), End: earliest(next(start), viewEnd)})
+	}
+
+	return periods
+}
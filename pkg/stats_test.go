@@ -0,0 +1,121 @@
+package ephemeris
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStats(t *testing.T) {
+	at := func(day, hour int) time.Time {
+		return time.Date(2024, time.March, day, hour, 0, 0, 0, newYork)
+	}
+
+	calendar := Calendar{
+		Entries: []Rule{
+			{
+				ID:                  "work",
+				Event:               Event{Start: at(9, 9), End: at(9, 17), Name: "Work", Metadata: &Metadata{Tags: []string{"billable"}}},
+				RepeatDaily:         1,
+				RepeatBackwardUntil: at(9, 9),
+			},
+			{
+				ID:    "night",
+				Event: Event{Start: at(9, 22), End: at(10, 6), Name: "Night Shift", Metadata: &Metadata{Tags: []string{"billable", "overtime"}}},
+			},
+		},
+		// The time filled by Idle is not busy
+		Idle: &Event{Name: "Idle"},
+	}
+
+	// Daylight saving time begins on March 10th 2024 in New York so the day
+	// only has 23 hours
+	stats, err := calendar.Stats(at(9, 0), at(11, 0), StatsOptions{Bucket: BucketDay, Location: newYork})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(stats.Buckets) != 2 {
+		t.Fatalf("Expected 2 days but got %v", stats.Buckets)
+	}
+	saturday, sunday := stats.Buckets[0], stats.Buckets[1]
+	if saturday.Duration() != 24*time.Hour || sunday.Duration() != 23*time.Hour {
+		t.Errorf("Expected days of 24h and 23h but got %s and %s", saturday.Duration(), sunday.Duration())
+	}
+
+	testCases := []struct {
+		desc     string
+		got      time.Duration
+		expected time.Duration
+	}{
+		{desc: "Night Shift Before Midnight", got: saturday.ByName["Night Shift"], expected: 2 * time.Hour},
+		{desc: "Night Shift After Midnight", got: sunday.ByName["Night Shift"], expected: 5 * time.Hour},
+		{desc: "Work By Rule", got: sunday.ByRule["work"], expected: 8 * time.Hour},
+		{desc: "Billable", got: sunday.ByTag["billable"], expected: 13 * time.Hour},
+		{desc: "Overtime", got: sunday.ByTag["overtime"], expected: 5 * time.Hour},
+		{desc: "Busy", got: saturday.Busy, expected: 10 * time.Hour},
+		{desc: "Total Busy", got: stats.Total.Busy, expected: 23 * time.Hour},
+		{desc: "Total Night Shift", got: stats.Total.ByName["Night Shift"], expected: 7 * time.Hour},
+		{desc: "Idle", got: stats.Total.ByName["Idle"], expected: 0},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			if tC.got != tC.expected {
+				t.Errorf("Expected %s but got %s", tC.expected, tC.got)
+			}
+		})
+	}
+
+	if expected := 100 * 13.0 / 23.0; sunday.Utilization != expected {
+		t.Errorf("Expected a utilization of %f but got %f", expected, sunday.Utilization)
+	}
+}
+
+func TestStatsBuckets(t *testing.T) {
+	at := func(month time.Month, day int) time.Time {
+		return time.Date(2024, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	testCases := []struct {
+		desc     string
+		bucket   Bucket
+		expected []Interval
+	}{
+		{
+			desc:     "None",
+			bucket:   BucketNone,
+			expected: nil,
+		},
+		{
+			desc:   "Week",
+			bucket: BucketWeek,
+			expected: []Interval{
+				{Start: at(time.March, 6), End: at(time.March, 11)},
+				{Start: at(time.March, 11), End: at(time.March, 18)},
+				{Start: at(time.March, 18), End: at(time.March, 25)},
+				{Start: at(time.March, 25), End: at(time.April, 1)},
+				{Start: at(time.April, 1), End: at(time.April, 2)},
+			},
+		},
+		{
+			desc:   "Month",
+			bucket: BucketMonth,
+			expected: []Interval{
+				{Start: at(time.March, 6), End: at(time.April, 1)},
+				{Start: at(time.April, 1), End: at(time.April, 2)},
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			got := buckets(at(time.March, 6), at(time.April, 2), StatsOptions{Bucket: tC.bucket})
+			if len(got) != len(tC.expected) {
+				t.Fatalf("Expected %v but got %v", tC.expected, got)
+			}
+			for i := range got {
+				if !got[i].Start.Equal(tC.expected[i].Start) || !got[i].End.Equal(tC.expected[i].End) {
+					t.Errorf("Expected %v but got %v", tC.expected[i], got[i])
+				}
+			}
+		})
+	}
+}
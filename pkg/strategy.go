@@ -3,10 +3,14 @@ package ephemeris
 // Esure BruteCondenser implements Condencer at compile time
 var _ Condencer = BruteCondenser{}
 
-type Condencer interface {
-	Condence([]Event) []Event
+// CondencerOf removes the overlaps from Events with a Payload of type T.
+type CondencerOf[T any] interface {
+	Condence([]EventOf[T]) []EventOf[T]
 }
 
+// Condencer a CondencerOf Events which are only described by their Name.
+type Condencer = CondencerOf[struct{}]
+
 type BruteCondenser struct{}
 
 // Condence squashes all the events recursively adding squashed events to a new slice and repeating over the new slice until there are no changes left.
@@ -0,0 +1,317 @@
+package ephemeris
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2/unstable"
+)
+
+// tomlParser builds a document from the expressions of a TOML file.
+type tomlParser struct {
+	parser unstable.Parser
+	root   *node
+	// table the table key values are added to, which is the last table
+	// header.
+	table *node
+	// defined the tables which have a table header, which can not be
+	// defined again by another header.
+	defined map[*node]bool
+}
+
+func parseTOML(data []byte) (*node, error) {
+	p := &tomlParser{root: &node{kind: nodeObject, line: 1}, defined: map[*node]bool{}}
+	p.table = p.root
+	p.parser.Reset(data)
+
+	for p.parser.NextExpression() {
+		expr := p.parser.Expression()
+
+		var err error
+		switch expr.Kind {
+		case unstable.KeyValue:
+			err = p.setKeyValue(p.table, expr)
+		case unstable.Table:
+			p.table, err = p.defineTable(expr)
+		case unstable.ArrayTable:
+			p.table, err = p.appendTable(expr)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var parserErr *unstable.ParserError
+	if err := p.parser.Error(); errors.As(err, &parserErr) {
+		return nil, lineError(p.line(p.parser.Range(parserErr.Highlight)), "", errors.New(parserErr.Message))
+	} else if err != nil {
+		return nil, err
+	}
+
+	return p.root, nil
+}
+
+// tomlKey a part of a dotted key.
+type tomlKey struct {
+	name string
+	line int
+}
+
+func (p *tomlParser) keys(it unstable.Iterator) []tomlKey {
+	var keys []tomlKey
+	for it.Next() {
+		keys = append(keys, tomlKey{name: string(it.Node().Data), line: p.line(it.Node().Raw)})
+	}
+	return keys
+}
+
+func (p *tomlParser) line(r unstable.Range) int {
+	return p.parser.Shape(r).Start.Line
+}
+
+// descend finds the table of the keys from the table, creating any tables
+// which do not exist yet. Arrays of tables descend into their last table.
+func (p *tomlParser) descend(table *node, keys []tomlKey) (*node, error) {
+	for _, key := range keys {
+		next := table.get(key.name)
+		if next == nil {
+			next = &node{kind: nodeObject, line: key.line}
+			table.fields = append(table.fields, field{key: key.name, line: key.line, value: next})
+		}
+		if next.kind == nodeArray && len(next.items) > 0 {
+			next = next.items[len(next.items)-1]
+		}
+		if next.kind != nodeObject {
+			return nil, lineError(key.line, key.name, errors.New("defined more than once"))
+		}
+
+		table = next
+	}
+
+	return table, nil
+}
+
+// defineTable finds the table of a Table expression, which may only be
+// defined once.
+func (p *tomlParser) defineTable(expr *unstable.Node) (*node, error) {
+	keys := p.keys(expr.Key())
+	table, err := p.descend(p.root, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.defined[table] {
+		key := keys[len(keys)-1]
+		return nil, lineError(key.line, key.name, errors.New("defined more than once"))
+	}
+	p.defined[table] = true
+	return table, nil
+}
+
+// appendTable adds a table to the array of tables of an ArrayTable
+// expression.
+func (p *tomlParser) appendTable(expr *unstable.Node) (*node, error) {
+	keys := p.keys(expr.Key())
+	parent, err := p.descend(p.root, keys[:len(keys)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	key := keys[len(keys)-1]
+	array := parent.get(key.name)
+	if array == nil {
+		array = &node{kind: nodeArray, line: key.line}
+		parent.fields = append(parent.fields, field{key: key.name, line: key.line, value: array})
+	}
+	if array.kind != nodeArray {
+		return nil, lineError(key.line, key.name, errors.New("defined more than once"))
+	}
+
+	table := &node{kind: nodeObject, line: key.line}
+	array.items = append(array.items, table)
+	return table, nil
+}
+
+func (p *tomlParser) setKeyValue(table *node, expr *unstable.Node) error {
+	keys := p.keys(expr.Key())
+	table, err := p.descend(table, keys[:len(keys)-1])
+	if err != nil {
+		return err
+	}
+
+	key := keys[len(keys)-1]
+	if table.get(key.name) != nil {
+		return lineError(key.line, key.name, errors.New("defined more than once"))
+	}
+
+	value, err := p.value(expr.Value())
+	if err != nil {
+		return err
+	}
+	table.fields = append(table.fields, field{key: key.name, line: key.line, value: value})
+	return nil
+}
+
+func (p *tomlParser) value(v *unstable.Node) (*node, error) {
+	n := &node{line: p.line(v.Raw)}
+
+	switch v.Kind {
+	case unstable.Array:
+		n.kind = nodeArray
+		it := v.Children()
+		for it.Next() {
+			item, err := p.value(it.Node())
+			if err != nil {
+				return nil, err
+			}
+			n.items = append(n.items, item)
+		}
+	case unstable.InlineTable:
+		n.kind = nodeObject
+		it := v.Children()
+		for it.Next() {
+			if err := p.setKeyValue(n, it.Node()); err != nil {
+				return nil, err
+			}
+		}
+	case unstable.Bool:
+		n.kind, n.value = nodeBool, string(v.Data)
+	case unstable.Integer:
+		i, err := strconv.ParseInt(string(v.Data), 0, 64)
+		if err != nil {
+			return nil, lineError(n.line, "", err)
+		}
+		n.kind, n.value = nodeNumber, strconv.FormatInt(i, 10)
+	case unstable.Float:
+		f, err := strconv.ParseFloat(strings.ReplaceAll(string(v.Data), "_", ""), 64)
+		if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+			return nil, lineError(n.line, "", fmt.Errorf("unsupported number %s", v.Data))
+		}
+		n.kind, n.value = nodeNumber, strconv.FormatFloat(f, 'g', -1, 64)
+	case unstable.String:
+		n.kind, n.value = nodeString, string(v.Data)
+	default:
+		// Dates and times are read as RFC 3339 timestamps, which TOML allows
+		// to have a space between the date and time
+		n.kind, n.value = nodeString, strings.Replace(string(v.Data), " ", "T", 1)
+	}
+
+	return n, nil
+}
+
+func writeTOML(w io.Writer, n *node) error {
+	var b bytes.Buffer
+	if err := writeTOMLTable(&b, "", n); err != nil {
+		return err
+	}
+	_, err := w.Write(bytes.TrimLeft(b.Bytes(), "\n"))
+	return err
+}
+
+// writeTOMLTable writes the fields of a table. Objects are written as tables
+// and arrays of objects as arrays of tables, after the other fields as the
+// fields of a table need to come before any of its subtables.
+func writeTOMLTable(b *bytes.Buffer, path string, table *node) error {
+	var tables []field
+	for _, f := range table.fields {
+		switch {
+		case f.value.kind == nodeNull:
+		case isTOMLTable(f.value):
+			tables = append(tables, f)
+		default:
+			fmt.Fprintf(b, "%s = ", tomlKeyName(f.key))
+			if err := writeTOMLValue(b, f.value); err != nil {
+				return err
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	for _, f := range tables {
+		subpath := path + tomlKeyName(f.key)
+		if f.value.kind == nodeObject {
+			fmt.Fprintf(b, "\n[%s]\n", subpath)
+			if err := writeTOMLTable(b, subpath+".", f.value); err != nil {
+				return err
+			}
+			continue
+		}
+
+		for _, item := range f.value.items {
+			fmt.Fprintf(b, "\n[[%s]]\n", subpath)
+			if err := writeTOMLTable(b, subpath+".", item); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// isTOMLTable whether the value is written as a table or an array of tables.
+func isTOMLTable(n *node) bool {
+	if n.kind == nodeObject {
+		return true
+	}
+	if n.kind != nodeArray || len(n.items) == 0 {
+		return false
+	}
+	for _, item := range n.items {
+		if item.kind != nodeObject {
+			return false
+		}
+	}
+	return true
+}
+
+func writeTOMLValue(b *bytes.Buffer, n *node) error {
+	switch n.kind {
+	case nodeBool, nodeNumber:
+		b.WriteString(n.value)
+	case nodeString:
+		// JSON escapes are a subset of the escapes of TOML basic strings
+		b.Write(appendJSONString(nil, n.value))
+	case nodeArray:
+		b.WriteString("[")
+		for i, item := range n.items {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			if err := writeTOMLValue(b, item); err != nil {
+				return err
+			}
+		}
+		b.WriteString("]")
+	case nodeObject:
+		b.WriteString("{")
+		for i, f := range n.fields {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			fmt.Fprintf(b, " %s = ", tomlKeyName(f.key))
+			if err := writeTOMLValue(b, f.value); err != nil {
+				return err
+			}
+		}
+		b.WriteString(" }")
+	default:
+		return errors.New("TOML does not support null values")
+	}
+
+	return nil
+}
+
+var tomlBareKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func tomlKeyName(key string) string {
+	if tomlBareKey.MatchString(key) {
+		return key
+	}
+	return string(appendJSONString(nil, key))
+}
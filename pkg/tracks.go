@@ -0,0 +1,142 @@
+package ephemeris
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// Track a resource which Events can be assigned to with their Track, such as
+// a room or a member of a team, so a Calendar can contain Events which take
+// place at the same time.
+type Track struct {
+	Name string `json:"name"`
+
+	// Capacity the number of Events which can take place on the Track at the
+	// same time, where 0 is the same as 1. A Track with a Capacity of 1 is
+	// condensed like a Calendar View, so only the Event with the highest
+	// priority is kept at any time and there are no Conflicts. With a larger
+	// Capacity all the Events are kept and the times where more than Capacity
+	// of them take place at once are Conflicts.
+	Capacity int `json:"capacity,omitempty"`
+}
+
+// ConflictOf a time where more Events are assigned to a Track than its
+// Capacity allows.
+type ConflictOf[T any] struct {
+	Track string
+	Interval
+	// Events the Events of the Track which take place during the Conflict,
+	// ordered by their Start.
+	Events []EventOf[T]
+}
+
+// Conflict a ConflictOf Events without a Payload.
+type Conflict = ConflictOf[struct{}]
+
+// TrackViews returns the Events of each Track, keyed by the Track name, for the
+// timeframe of viewStart(inclusive) and viewEnd(exclusive). Events without a
+// Track are on the Track named "". Tracks only contain the Events assigned to
+// them so Events on different Tracks never affect each other.
+//
+// Each Track with a Capacity of 1 is condensed on its own like View, including
+// the Merge, MinimumDuration and Idle settings of the Calendar. Tracks with a
+// larger Capacity are the exception as their Events are meant to take place at
+// the same time: they are not condensed and none of those settings apply.
+// Their Events are kept as they are, ordered by their Start, and the times
+// where they exceed the Capacity of the Track are returned as Conflicts,
+// ordered by their Start.
+func (c *CalendarOf[T]) TrackViews(viewStart, viewEnd time.Time) (map[string][]EventOf[T], []ConflictOf[T], error) {
+	expandedEvents, err := c.expandEntries(viewStart, viewEnd)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	eventsByTrack := map[string][]EventOf[T]{}
+	for _, event := range expandedEvents {
+		eventsByTrack[event.Track] = append(eventsByTrack[event.Track], event)
+	}
+
+	views := map[string][]EventOf[T]{}
+	var conflicts []ConflictOf[T]
+	for name, events := range eventsByTrack {
+		capacity := c.capacity(name)
+		if capacity == 1 {
+			views[name] = c.condense(events, viewStart, viewEnd)
+			continue
+		}
+
+		slices.SortStableFunc(events, func(a, b EventOf[T]) int {
+			return a.Start.Compare(b.Start)
+		})
+		views[name] = events
+		conflicts = append(conflicts, overCapacity(name, events, capacity)...)
+	}
+
+	slices.SortStableFunc(conflicts, func(a, b ConflictOf[T]) int {
+		if order := a.Start.Compare(b.Start); order != 0 {
+			return order
+		}
+		return strings.Compare(a.Track, b.Track)
+	})
+
+	return views, conflicts, nil
+}
+
+// capacity the Capacity of the Track with the name, which is 1 for Tracks
+// which are not listed or do not set it.
+func (c *CalendarOf[T]) capacity(name string) int {
+	for _, track := range c.Tracks {
+		if track.Name == name && track.Capacity > 0 {
+			return track.Capacity
+		}
+	}
+	return 1
+}
+
+// overCapacity finds the Conflicts where more than capacity of the Events,
+// which are ordered by their Start, take place at once.
+func overCapacity[T any](track string, events []EventOf[T], capacity int) []ConflictOf[T] {
+	type change struct {
+		at    time.Time
+		delta int
+	}
+
+	var changes []change
+	for _, event := range events {
+		if event.IsInstant() {
+			continue
+		}
+		changes = append(changes, change{at: event.Start, delta: 1}, change{at: event.effectiveEnd(), delta: -1})
+	}
+	slices.SortStableFunc(changes, func(a, b change) int {
+		if order := a.at.Compare(b.at); order != 0 {
+			return order
+		}
+		// Events which touch do not take place at the same time
+		return a.delta - b.delta
+	})
+
+	var conflicts []ConflictOf[T]
+	var conflictStart time.Time
+	concurrent, inConflict := 0, false
+	for _, change := range changes {
+		concurrent += change.delta
+		if concurrent > capacity && !inConflict {
+			conflictStart, inConflict = change.at, true
+		}
+		if concurrent <= capacity && inConflict {
+			inConflict = false
+
+			conflict := ConflictOf[T]{Track: track, Interval: Interval{Start: conflictStart, End: change.at}}
+			for _, event := range events {
+				if !event.IsInstant() && event.Start.Before(conflict.End) && event.effectiveEnd().After(conflict.Start) {
+					conflict.Events = append(conflict.Events, event)
+				}
+			}
+			conflicts = append(conflicts, conflict)
+		}
+	}
+
+	return conflicts
+}
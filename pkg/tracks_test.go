@@ -0,0 +1,105 @@
+package ephemeris
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestTrackViews(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, time.March, 4, hour, minute, 0, 0, time.UTC)
+	}
+
+	calendar := Calendar{
+		Entries: []Rule{
+			{Event: Event{Start: at(9, 0), End: at(17, 0), Name: "Shift", Track: "alice"}},
+			{Event: Event{Start: at(12, 0), End: at(13, 0), Name: "Lunch", Track: "alice"}},
+			{Event: Event{Start: at(9, 0), End: at(12, 0), Name: "Shift", Track: "bob"}},
+			{Event: Event{Start: at(10, 0), End: at(11, 0), Name: "Planning", Track: "room"}},
+			{Event: Event{Start: at(10, 30), End: at(12, 0), Name: "Design", Track: "room"}},
+			{Event: Event{Start: at(10, 45), End: at(11, 15), Name: "Interview", Track: "room"}},
+			{Event: Event{Start: at(12, 0), End: at(13, 0), Name: "Retro", Track: "room"}},
+		},
+		// Setting a Capacity of 1 is the same as not listing the Track
+		Tracks: []Track{{Name: "alice", Capacity: 1}, {Name: "room", Capacity: 2}},
+	}
+
+	views, conflicts, err := calendar.TrackViews(at(0, 0), at(24, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedViews := map[string][]Event{
+		"alice": {
+			{Start: at(9, 0), End: at(12, 0), Name: "Shift", RecurrenceID: at(9, 0), Track: "alice", Truncated: true, Split: true},
+			{Start: at(12, 0), End: at(13, 0), Name: "Lunch", RecurrenceID: at(12, 0), Track: "alice"},
+			{Start: at(13, 0), End: at(17, 0), Name: "Shift", RecurrenceID: at(9, 0), Track: "alice", Truncated: true, Split: true},
+		},
+		"bob": {
+			{Start: at(9, 0), End: at(12, 0), Name: "Shift", RecurrenceID: at(9, 0), Track: "bob"},
+		},
+		"room": {
+			{Start: at(10, 0), End: at(11, 0), Name: "Planning", RecurrenceID: at(10, 0), Track: "room"},
+			{Start: at(10, 30), End: at(12, 0), Name: "Design", RecurrenceID: at(10, 30), Track: "room"},
+			{Start: at(10, 45), End: at(11, 15), Name: "Interview", RecurrenceID: at(10, 45), Track: "room"},
+			{Start: at(12, 0), End: at(13, 0), Name: "Retro", RecurrenceID: at(12, 0), Track: "room"},
+		},
+	}
+	if len(views) != len(expectedViews) {
+		t.Errorf("Expected %d tracks but got %d", len(expectedViews), len(views))
+	}
+	for track, expected := range expectedViews {
+		if got := views[track]; !slices.Equal(expected, got) {
+			t.Errorf("Expected %s to have %v but got %v", track, expected, got)
+		}
+	}
+
+	if len(conflicts) != 1 {
+		t.Fatalf("Expected a single conflict but got %v", conflicts)
+	}
+	conflict := conflicts[0]
+	if conflict.Track != "room" || !conflict.Start.Equal(at(10, 45)) || !conflict.End.Equal(at(11, 0)) {
+		t.Errorf("Expected the room to be over capacity from 10:45 to 11:00 but got %v", conflict)
+	}
+	if expected := expectedViews["room"][:3]; !slices.Equal(expected, conflict.Events) {
+		t.Errorf("Expected the conflict to have %v but got %v", expected, conflict.Events)
+	}
+}
+
+func TestTrackViewsAboveCapacity(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, time.March, 4, hour, minute, 0, 0, time.UTC)
+	}
+
+	// None of the settings for condensing apply to a Track with a Capacity
+	// above 1
+	calendar := Calendar{
+		Entries: []Rule{
+			{Event: Event{Start: at(9, 0), End: at(10, 0), Name: "Sync", Track: "room"}},
+			{Event: Event{Start: at(10, 0), End: at(11, 0), Name: "Sync", Track: "room"}},
+			{Event: Event{Start: at(9, 50), End: at(10, 10), Name: "Talk", Track: "room"}},
+		},
+		Merge:           MergeByName,
+		MinimumDuration: time.Hour,
+		Idle:            &Event{Name: "Free"},
+		Tracks:          []Track{{Name: "room", Capacity: 2}},
+	}
+
+	views, conflicts, err := calendar.TrackViews(at(8, 0), at(12, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Event{
+		{Start: at(9, 0), End: at(10, 0), Name: "Sync", RecurrenceID: at(9, 0), Track: "room"},
+		{Start: at(9, 50), End: at(10, 10), Name: "Talk", RecurrenceID: at(9, 50), Track: "room"},
+		{Start: at(10, 0), End: at(11, 0), Name: "Sync", RecurrenceID: at(10, 0), Track: "room"},
+	}
+	if got := views["room"]; !slices.Equal(expected, got) {
+		t.Errorf("Expected %v but got %v", expected, got)
+	}
+	if len(conflicts) != 0 {
+		t.Errorf("Expected no conflicts but got %v", conflicts)
+	}
+}
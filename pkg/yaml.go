@@ -0,0 +1,130 @@
+package ephemeris
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// parseYAML reads the first document of a YAML file, an empty file is an
+// empty object.
+func parseYAML(data []byte) (*node, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	if len(document.Content) == 0 {
+		return &node{kind: nodeObject, line: 1}, nil
+	}
+
+	return fromYAML(document.Content[0])
+}
+
+func fromYAML(y *yaml.Node) (*node, error) {
+	n := &node{line: y.Line}
+
+	switch y.Kind {
+	case yaml.AliasNode:
+		return fromYAML(y.Alias)
+	case yaml.MappingNode:
+		n.kind = nodeObject
+		for i := 0; i+1 < len(y.Content); i += 2 {
+			key, value := y.Content[i], y.Content[i+1]
+			if key.Kind != yaml.ScalarNode {
+				return nil, lineError(key.Line, "", fmt.Errorf("expected a key but got a %s", key.ShortTag()))
+			}
+			if n.get(key.Value) != nil {
+				return nil, lineError(key.Line, key.Value, errors.New("defined more than once"))
+			}
+
+			v, err := fromYAML(value)
+			if err != nil {
+				return nil, err
+			}
+			n.fields = append(n.fields, field{key: key.Value, line: key.Line, value: v})
+		}
+	case yaml.SequenceNode:
+		n.kind = nodeArray
+		for _, item := range y.Content {
+			i, err := fromYAML(item)
+			if err != nil {
+				return nil, err
+			}
+			n.items = append(n.items, i)
+		}
+	default:
+		switch y.ShortTag() {
+		case "!!null":
+			n.kind = nodeNull
+		case "!!bool":
+			var b bool
+			if err := y.Decode(&b); err != nil {
+				return nil, err
+			}
+			n.kind, n.value = nodeBool, strconv.FormatBool(b)
+		case "!!int":
+			var i int64
+			if err := y.Decode(&i); err != nil {
+				return nil, err
+			}
+			n.kind, n.value = nodeNumber, strconv.FormatInt(i, 10)
+		case "!!float":
+			var f float64
+			if err := y.Decode(&f); err != nil {
+				return nil, err
+			}
+			if math.IsInf(f, 0) || math.IsNaN(f) {
+				return nil, lineError(y.Line, "", fmt.Errorf("unsupported number %s", y.Value))
+			}
+			n.kind, n.value = nodeNumber, strconv.FormatFloat(f, 'g', -1, 64)
+		default:
+			// Timestamps are kept as they are written so they are read the
+			// same way as in JSON
+			n.kind, n.value = nodeString, y.Value
+		}
+	}
+
+	return n, nil
+}
+
+func writeYAML(w io.Writer, n *node) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(toYAML(n)); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+func toYAML(n *node) *yaml.Node {
+	switch n.kind {
+	case nodeObject:
+		y := &yaml.Node{Kind: yaml.MappingNode}
+		for _, f := range n.fields {
+			y.Content = append(y.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: f.key}, toYAML(f.value))
+		}
+		return y
+	case nodeArray:
+		y := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, item := range n.items {
+			y.Content = append(y.Content, toYAML(item))
+		}
+		return y
+	case nodeBool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: n.value}
+	case nodeNumber:
+		tag := "!!float"
+		if n.isInteger() {
+			tag = "!!int"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: n.value}
+	case nodeString:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: n.value}
+	default:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+	}
+}